@@ -0,0 +1,58 @@
+package main
+
+// Run with: go run cmd/examples/dynamicfee/main.go
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/donghquinn/go-blockchain-helper/pkg/web3"
+)
+
+func main() {
+	fmt.Println("=== EIP-1559 Dynamic Fee Transaction Examples ===")
+
+	// Well-known test key, never use it for real funds
+	privateKey := "0x4646464646464646464646464646464646464646464646464646464646464646"
+	to := "0x3535353535353535353535353535353535353535"
+
+	tx := &web3.Transaction{
+		Type:                 web3.DynamicFeeTxType,
+		ChainID:              big.NewInt(1),
+		Nonce:                0,
+		To:                   to,
+		Value:                web3.EtherToWei(0.1),
+		Gas:                  21000,
+		MaxFeePerGas:         web3.GweiToWei(50),
+		MaxPriorityFeePerGas: web3.GweiToWei(2),
+	}
+
+	// Fee calculation
+	fmt.Println("\n--- Fee Calculation ---")
+	for _, gwei := range []float64{10, 30, 60} {
+		baseFee := web3.GweiToWei(gwei)
+		fmt.Printf("Base fee %s Gwei:\n", web3.FormatGwei(baseFee, 0))
+		fmt.Printf("  Effective gas price: %s Gwei\n", web3.FormatGwei(tx.EffectiveGasPrice(baseFee), 2))
+		fmt.Printf("  Fee: %s ETH\n", web3.FormatEther(tx.CalculateFeeWithBaseFee(baseFee), 6))
+	}
+	fmt.Printf("Max cost: %s ETH\n", web3.FormatEther(tx.MaxCost(), 6))
+
+	// Signing
+	fmt.Println("\n--- Signing ---")
+	fmt.Printf("Signing hash: %s\n", tx.Hash())
+
+	signed, err := web3.SignTransaction(tx, privateKey, tx.ChainID)
+	if err != nil {
+		fmt.Printf("Error signing transaction: %v\n", err)
+		return
+	}
+	fmt.Printf("Raw transaction: %s\n", signed.RawHex())
+	fmt.Printf("Transaction hash: %s\n", signed.Hash())
+
+	sender, err := web3.RecoverSender(signed.RawHex())
+	if err != nil {
+		fmt.Printf("Error recovering sender: %v\n", err)
+		return
+	}
+	fmt.Printf("Recovered sender: %s\n", sender)
+}
@@ -118,7 +118,7 @@ func main() {
 	topics := []string{
 		"0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef", // Transfer event signature
 		"0x000000000000000000000000742d35cc6634c0532925a3b8d82c28d53e01bcf2", // from
-		"0x0000000000000000000000008ba1f109551bd432803012645hac136c63f5e5",   // to
+		"0x0000000000000000000000008ba1f109551bd432803012645ac136ddd64dba72", // to
 	}
 	logData := "0x00000000000000000000000000000000000000000000000000000000000f4240" // 1 USDC
 
@@ -60,7 +60,7 @@ func main() {
 	fmt.Println("\n--- Gas Estimation ---")
 
 	// Simple ETH transfer
-	gasETH, err := web3.EstimateGas(to, from, "", value)
+	gasETH, err := web3.EstimateGasOffline(to, from, "", value)
 	if err != nil {
 		fmt.Printf("Error estimating gas for ETH transfer: %v\n", err)
 	} else {
@@ -68,7 +68,7 @@ func main() {
 	}
 
 	// Contract call
-	gasContract, err := web3.EstimateGas(to, from, "0xa9059cbb000000000000000000000000742d35cc6634c0532925a3b8d82c28d53e01bcf200000000000000000000000000000000000000000000000000000000000f4240", big.NewInt(0))
+	gasContract, err := web3.EstimateGasOffline(to, from, "0xa9059cbb000000000000000000000000742d35cc6634c0532925a3b8d82c28d53e01bcf200000000000000000000000000000000000000000000000000000000000f4240", big.NewInt(0))
 	if err != nil {
 		fmt.Printf("Error estimating gas for contract call: %v\n", err)
 	} else {
@@ -103,7 +103,7 @@ func main() {
 
 	// Gas price suggestion
 	fmt.Println("\n--- Gas Price Suggestion ---")
-	suggestedGasPrice := web3.SuggestGasPrice()
+	suggestedGasPrice := web3.SuggestGasPriceDefault()
 	fmt.Printf("Suggested Gas Price: %s Wei (%s Gwei)\n", suggestedGasPrice.String(), web3.FormatGwei(suggestedGasPrice, 2))
 
 	// Transaction with custom gas settings
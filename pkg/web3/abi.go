@@ -4,14 +4,17 @@ import (
 	"encoding/hex"
 	"fmt"
 	"math/big"
+	"reflect"
 	"regexp"
 	"strconv"
 	"strings"
 )
 
 type ABIParam struct {
-	Name string
-	Type string
+	Name       string
+	Type       string
+	Indexed    bool
+	Components []ABIParam
 }
 
 type ABIFunction struct {
@@ -42,10 +45,17 @@ func EncodeFunctionCall(funcName string, params []ABIParam, values []interface{}
 	return append(selectorBytes, encodedParams...), nil
 }
 
+func ExtendedSelector(signature string, bytes int) ([]byte, error) {
+	if bytes < 1 || bytes > 32 {
+		return nil, fmt.Errorf("selector length must be between 1 and 32 bytes, got %d", bytes)
+	}
+	return keccak256([]byte(signature))[:bytes], nil
+}
+
 func createFunctionSignature(funcName string, params []ABIParam) string {
 	var paramTypes []string
 	for _, param := range params {
-		paramTypes = append(paramTypes, param.Type)
+		paramTypes = append(paramTypes, param.canonicalType())
 	}
 	return funcName + "(" + strings.Join(paramTypes, ",") + ")"
 }
@@ -55,30 +65,38 @@ func encodeParameters(params []ABIParam, values []interface{}) ([]byte, error) {
 		return nil, fmt.Errorf("parameter count mismatch: expected %d, got %d", len(params), len(values))
 	}
 
+	encodedValues := make([][]byte, len(params))
+	headSize := 0
+
+	for i, param := range params {
+		abiType := param.canonicalType()
+		encodedValue, err := encodeValue(abiType, values[i])
+		if err != nil {
+			return nil, fmt.Errorf("parameter %d (%s): cannot encode Go type %T: %w", i, abiType, values[i], err)
+		}
+		encodedValues[i] = encodedValue
+
+		if isDynamicType(abiType) {
+			headSize += 32
+		} else {
+			headSize += len(encodedValue)
+		}
+	}
+
 	var encoded []byte
 	var dynamicData []byte
-	dynamicOffset := len(params) * 32
+	dynamicOffset := headSize
 
 	for i, param := range params {
-		value := values[i]
-
-		if isDynamicType(param.Type) {
+		if isDynamicType(param.canonicalType()) {
 			offsetBytes := make([]byte, 32)
 			big.NewInt(int64(dynamicOffset)).FillBytes(offsetBytes)
 			encoded = append(encoded, offsetBytes...)
 
-			dynamicEncoded, err := encodeValue(param.Type, value)
-			if err != nil {
-				return nil, fmt.Errorf("failed to encode dynamic parameter %d: %w", i, err)
-			}
-			dynamicData = append(dynamicData, dynamicEncoded...)
-			dynamicOffset += len(dynamicEncoded)
+			dynamicData = append(dynamicData, encodedValues[i]...)
+			dynamicOffset += len(encodedValues[i])
 		} else {
-			staticEncoded, err := encodeValue(param.Type, value)
-			if err != nil {
-				return nil, fmt.Errorf("failed to encode static parameter %d: %w", i, err)
-			}
-			encoded = append(encoded, staticEncoded...)
+			encoded = append(encoded, encodedValues[i]...)
 		}
 	}
 
@@ -86,11 +104,109 @@ func encodeParameters(params []ABIParam, values []interface{}) ([]byte, error) {
 }
 
 func isDynamicType(abiType string) bool {
-	return strings.Contains(abiType, "[]") || abiType == "string" || abiType == "bytes"
+	if strings.HasSuffix(abiType, "[]") {
+		return true
+	}
+	if elementType, _, ok := parseFixedArrayType(abiType); ok {
+		return isDynamicType(elementType)
+	}
+	if components, ok := tupleComponentTypes(abiType); ok {
+		for _, component := range components {
+			if isDynamicType(component) {
+				return true
+			}
+		}
+		return false
+	}
+	return abiType == "string" || abiType == "bytes"
+}
+
+// tupleComponentTypes splits a canonical tuple type such as
+// "(uint256,(address,bool))" into its top-level component types.
+func tupleComponentTypes(abiType string) ([]string, bool) {
+	if !strings.HasPrefix(abiType, "(") || matchingParen(abiType, 0) != len(abiType)-1 {
+		return nil, false
+	}
+
+	inner := abiType[1 : len(abiType)-1]
+	if inner == "" {
+		return []string{}, true
+	}
+	components, err := splitABIList(inner)
+	if err != nil {
+		return nil, false
+	}
+	for i, component := range components {
+		components[i] = strings.TrimSpace(component)
+	}
+	return components, true
+}
+
+// canonicalType expands JSON-ABI style "tuple" types ("tuple", "tuple[]")
+// into their parenthesized form using Components.
+func (param ABIParam) canonicalType() string {
+	if !strings.HasPrefix(param.Type, "tuple") || len(param.Components) == 0 {
+		return param.Type
+	}
+
+	types := make([]string, len(param.Components))
+	for i, component := range param.Components {
+		types[i] = component.canonicalType()
+	}
+	return "(" + strings.Join(types, ",") + ")" + strings.TrimPrefix(param.Type, "tuple")
+}
+
+func encodeTuple(abiType string, value interface{}) ([]byte, error) {
+	components, ok := tupleComponentTypes(abiType)
+	if !ok {
+		return nil, fmt.Errorf("invalid tuple type: %s", abiType)
+	}
+
+	values, err := toInterfaceSlice(value)
+	if err != nil {
+		return nil, err
+	}
+	if len(values) != len(components) {
+		return nil, fmt.Errorf("%s requires %d components, got %d", abiType, len(components), len(values))
+	}
+
+	encoded := make([][]byte, len(components))
+	headSize := 0
+	for i, component := range components {
+		encoded[i], err = encodeValue(component, values[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode tuple component %d: %w", i, err)
+		}
+		if isDynamicType(component) {
+			headSize += 32
+		} else {
+			headSize += len(encoded[i])
+		}
+	}
+
+	var heads, tails []byte
+	for i, component := range components {
+		if !isDynamicType(component) {
+			heads = append(heads, encoded[i]...)
+			continue
+		}
+		offsetBytes := make([]byte, 32)
+		big.NewInt(int64(headSize + len(tails))).FillBytes(offsetBytes)
+		heads = append(heads, offsetBytes...)
+		tails = append(tails, encoded[i]...)
+	}
+
+	return append(heads, tails...), nil
 }
 
 func encodeValue(abiType string, value interface{}) ([]byte, error) {
 	switch {
+	case strings.HasSuffix(abiType, "[]"):
+		return encodeArray(abiType, value)
+	case strings.HasSuffix(abiType, "]"):
+		return encodeFixedArray(abiType, value)
+	case strings.HasPrefix(abiType, "("):
+		return encodeTuple(abiType, value)
 	case abiType == "address":
 		return encodeAddress(value)
 	case strings.HasPrefix(abiType, "uint"):
@@ -103,8 +219,8 @@ func encodeValue(abiType string, value interface{}) ([]byte, error) {
 		return encodeString(value)
 	case abiType == "bytes":
 		return encodeBytes(value)
-	case strings.HasSuffix(abiType, "[]"):
-		return encodeArray(abiType, value)
+	case isFixedBytesType(abiType):
+		return encodeFixedBytes(abiType, value)
 	default:
 		return nil, fmt.Errorf("unsupported type: %s", abiType)
 	}
@@ -116,39 +232,78 @@ func encodeAddress(value interface{}) ([]byte, error) {
 	case string:
 		addressStr = v
 	default:
-		return nil, fmt.Errorf("address must be string")
+		return nil, fmt.Errorf("address must be a hex string")
 	}
 
-	if !ValidateAddress(addressStr) {
-		return nil, fmt.Errorf("invalid address format")
+	return encodeAddressWord(addressStr)
+}
+
+func encodeAddressWord(address string) ([]byte, error) {
+	if !ValidateAddress(address) {
+		return nil, fmt.Errorf("invalid address format: %q", address)
 	}
 
-	addressBytes, _ := hex.DecodeString(strings.TrimPrefix(addressStr, "0x"))
-	result := make([]byte, 32)
-	copy(result[12:], addressBytes)
-	return result, nil
-}
+	addressBytes, err := normalizeHex(address)
+	if err != nil || len(addressBytes) != 20 {
+		return nil, fmt.Errorf("invalid address format: %q", address)
+	}
 
-func encodeUint(abiType string, value interface{}) ([]byte, error) {
-	var bigIntValue *big.Int
+	word := make([]byte, 32)
+	copy(word[12:], addressBytes)
+	return word, nil
+}
 
+func toBigInt(value interface{}) (*big.Int, error) {
 	switch v := value.(type) {
 	case *big.Int:
-		bigIntValue = v
+		if v == nil {
+			return nil, fmt.Errorf("integer value is nil")
+		}
+		return v, nil
 	case string:
-		var ok bool
-		bigIntValue, ok = new(big.Int).SetString(v, 10)
+		bigIntValue, ok := new(big.Int).SetString(v, 10)
 		if !ok {
-			return nil, fmt.Errorf("invalid uint string")
-		}
-	case int:
-		bigIntValue = big.NewInt(int64(v))
-	case int64:
-		bigIntValue = big.NewInt(v)
-	case uint64:
-		bigIntValue = new(big.Int).SetUint64(v)
+			return nil, fmt.Errorf("invalid integer string %q", v)
+		}
+		return bigIntValue, nil
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return big.NewInt(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return new(big.Int).SetUint64(rv.Uint()), nil
 	default:
-		return nil, fmt.Errorf("unsupported uint type")
+		return nil, fmt.Errorf("unsupported integer value type %T", value)
+	}
+}
+
+func integerBits(abiType, prefix string) (int, error) {
+	width := strings.TrimPrefix(abiType, prefix)
+	if width == "" {
+		return 256, nil
+	}
+
+	bits, err := strconv.Atoi(width)
+	if err != nil || bits%8 != 0 || bits < 8 || bits > 256 || width[0] == '0' {
+		return 0, fmt.Errorf("invalid integer type %s", abiType)
+	}
+	return bits, nil
+}
+
+func encodeUint(abiType string, value interface{}) ([]byte, error) {
+	bits, err := integerBits(abiType, "uint")
+	if err != nil {
+		return nil, err
+	}
+	bigIntValue, err := toBigInt(value)
+	if err != nil {
+		return nil, err
+	}
+
+	if bigIntValue.Sign() < 0 || bigIntValue.BitLen() > bits {
+		return nil, fmt.Errorf("value %s out of range for %s", bigIntValue.String(), abiType)
 	}
 
 	result := make([]byte, 32)
@@ -157,7 +312,28 @@ func encodeUint(abiType string, value interface{}) ([]byte, error) {
 }
 
 func encodeInt(abiType string, value interface{}) ([]byte, error) {
-	return encodeUint(abiType, value)
+	bits, err := integerBits(abiType, "int")
+	if err != nil {
+		return nil, err
+	}
+	bigIntValue, err := toBigInt(value)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := new(big.Int).Lsh(big.NewInt(1), uint(bits-1))
+	if bigIntValue.Cmp(limit) >= 0 || bigIntValue.Cmp(new(big.Int).Neg(limit)) < 0 {
+		return nil, fmt.Errorf("value %s out of range for %s", bigIntValue.String(), abiType)
+	}
+
+	twosComplement := new(big.Int).Set(bigIntValue)
+	if twosComplement.Sign() < 0 {
+		twosComplement.Add(twosComplement, new(big.Int).Lsh(big.NewInt(1), 256))
+	}
+
+	result := make([]byte, 32)
+	twosComplement.FillBytes(result)
+	return result, nil
 }
 
 func encodeBool(value interface{}) ([]byte, error) {
@@ -207,9 +383,9 @@ func encodeBytes(value interface{}) ([]byte, error) {
 		bytes = v
 	case string:
 		var err error
-		bytes, err = hex.DecodeString(strings.TrimPrefix(v, "0x"))
+		bytes, err = normalizeHex(v)
 		if err != nil {
-			return nil, fmt.Errorf("invalid hex string")
+			return nil, err
 		}
 	default:
 		return nil, fmt.Errorf("bytes value must be []byte or hex string")
@@ -223,25 +399,160 @@ func encodeBytes(value interface{}) ([]byte, error) {
 	return append(length, paddedBytes...), nil
 }
 
-func encodeArray(abiType string, value interface{}) ([]byte, error) {
-	elementType := strings.TrimSuffix(abiType, "[]")
+func isFixedBytesType(abiType string) bool {
+	_, err := fixedBytesSize(abiType)
+	return err == nil
+}
+
+func fixedBytesSize(abiType string) (int, error) {
+	if !strings.HasPrefix(abiType, "bytes") || abiType == "bytes" {
+		return 0, fmt.Errorf("not a fixed bytes type: %s", abiType)
+	}
+
+	size, err := strconv.Atoi(strings.TrimPrefix(abiType, "bytes"))
+	if err != nil || size < 1 || size > 32 {
+		return 0, fmt.Errorf("invalid fixed bytes type: %s", abiType)
+	}
+	return size, nil
+}
+
+func encodeFixedBytes(abiType string, value interface{}) ([]byte, error) {
+	size, err := fixedBytesSize(abiType)
+	if err != nil {
+		return nil, err
+	}
+
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case [32]byte:
+		bytes = v[:]
+	case [4]byte:
+		bytes = v[:]
+	case string:
+		bytes, err = normalizeHex(v)
+		if err != nil {
+			return nil, err
+		}
+		// A hex string of the wrong width is almost always a typo rather
+		// than an intentionally short value, so require the exact size.
+		if len(bytes) != size {
+			return nil, fmt.Errorf("%s hex value must be %d bytes, got %d", abiType, size, len(bytes))
+		}
+	default:
+		return nil, fmt.Errorf("%s value must be []byte, byte array or hex string", abiType)
+	}
+
+	if len(bytes) > size {
+		return nil, fmt.Errorf("%s value too long: %d bytes", abiType, len(bytes))
+	}
+
+	result := make([]byte, 32)
+	copy(result, bytes)
+	return result, nil
+}
+
+func parseFixedArrayType(abiType string) (string, int, bool) {
+	if !strings.HasSuffix(abiType, "]") {
+		return "", 0, false
+	}
+
+	open := strings.LastIndex(abiType, "[")
+	if open <= 0 {
+		return "", 0, false
+	}
+
+	size, err := strconv.Atoi(abiType[open+1 : len(abiType)-1])
+	if err != nil || size < 1 {
+		return "", 0, false
+	}
 
-	var elements []interface{}
+	return abiType[:open], size, true
+}
+
+func toInterfaceSlice(value interface{}) ([]interface{}, error) {
 	switch v := value.(type) {
 	case []interface{}:
-		elements = v
+		return v, nil
 	case []string:
-		elements = make([]interface{}, len(v))
+		elements := make([]interface{}, len(v))
 		for i, s := range v {
 			elements[i] = s
 		}
-	default:
+		return elements, nil
+	}
+
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
 		return nil, fmt.Errorf("array value must be slice")
 	}
 
+	elements := make([]interface{}, rv.Len())
+	for i := range elements {
+		elements[i] = rv.Index(i).Interface()
+	}
+	return elements, nil
+}
+
+func encodeArray(abiType string, value interface{}) ([]byte, error) {
+	elementType := strings.TrimSuffix(abiType, "[]")
+
+	elements, err := toInterfaceSlice(value)
+	if err != nil {
+		return nil, err
+	}
+
 	length := make([]byte, 32)
 	big.NewInt(int64(len(elements))).FillBytes(length)
 
+	encodedElements, err := encodeSequence(elementType, elements)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(length, encodedElements...), nil
+}
+
+func encodeFixedArray(abiType string, value interface{}) ([]byte, error) {
+	elementType, size, ok := parseFixedArrayType(abiType)
+	if !ok {
+		return nil, fmt.Errorf("invalid fixed array type: %s", abiType)
+	}
+
+	elements, err := toInterfaceSlice(value)
+	if err != nil {
+		return nil, err
+	}
+	if len(elements) != size {
+		return nil, fmt.Errorf("%s requires %d elements, got %d", abiType, size, len(elements))
+	}
+
+	return encodeSequence(elementType, elements)
+}
+
+func encodeSequence(elementType string, elements []interface{}) ([]byte, error) {
+	if isDynamicType(elementType) {
+		var heads []byte
+		var tails []byte
+		tailOffset := len(elements) * 32
+
+		for _, element := range elements {
+			encoded, err := encodeValue(elementType, element)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode array element: %w", err)
+			}
+
+			offsetBytes := make([]byte, 32)
+			big.NewInt(int64(tailOffset)).FillBytes(offsetBytes)
+			heads = append(heads, offsetBytes...)
+			tails = append(tails, encoded...)
+			tailOffset += len(encoded)
+		}
+
+		return append(heads, tails...), nil
+	}
+
 	var encodedElements []byte
 	for _, element := range elements {
 		encoded, err := encodeValue(elementType, element)
@@ -251,7 +562,7 @@ func encodeArray(abiType string, value interface{}) ([]byte, error) {
 		encodedElements = append(encodedElements, encoded...)
 	}
 
-	return append(length, encodedElements...), nil
+	return encodedElements, nil
 }
 
 func padBytes(data []byte, blockSize int) []byte {
@@ -268,6 +579,16 @@ func padBytes(data []byte, blockSize int) []byte {
 }
 
 func DecodeFunctionResult(abiTypes []string, data []byte) ([]interface{}, error) {
+	return decodeFunctionResult(abiTypes, data, false)
+}
+
+// DecodeFunctionResultStrict also rejects address words whose upper 12 bytes
+// are not zero, which usually means the data does not match abiTypes.
+func DecodeFunctionResultStrict(abiTypes []string, data []byte) ([]interface{}, error) {
+	return decodeFunctionResult(abiTypes, data, true)
+}
+
+func decodeFunctionResult(abiTypes []string, data []byte, strict bool) ([]interface{}, error) {
 	if len(data) == 0 {
 		return nil, fmt.Errorf("empty data")
 	}
@@ -280,7 +601,7 @@ func DecodeFunctionResult(abiTypes []string, data []byte) ([]interface{}, error)
 			return nil, fmt.Errorf("insufficient data for type %s", abiType)
 		}
 
-		value, newOffset, err := decodeValue(abiType, data, offset)
+		value, newOffset, err := decodeValue(abiType, data, offset, strict)
 		if err != nil {
 			return nil, fmt.Errorf("failed to decode type %s: %w", abiType, err)
 		}
@@ -292,26 +613,152 @@ func DecodeFunctionResult(abiTypes []string, data []byte) ([]interface{}, error)
 	return results, nil
 }
 
-func decodeValue(abiType string, data []byte, offset int) (interface{}, int, error) {
+func DecodeCustomError(data []byte, errorDefs []ABIFunction) (string, []interface{}, error) {
+	if len(data) < 4 {
+		return "", nil, fmt.Errorf("revert data too short for error selector")
+	}
+
+	selector := hex.EncodeToString(data[:4])
+	for _, def := range errorDefs {
+		signature := createFunctionSignature(def.Name, def.Inputs)
+		if Keccak256([]byte(signature))[:8] != selector {
+			continue
+		}
+
+		if len(def.Inputs) == 0 {
+			return def.Name, []interface{}{}, nil
+		}
+
+		abiTypes := make([]string, len(def.Inputs))
+		for i, input := range def.Inputs {
+			abiTypes[i] = input.canonicalType()
+		}
+
+		args, err := DecodeFunctionResult(abiTypes, data[4:])
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to decode %s arguments: %w", signature, err)
+		}
+		return def.Name, args, nil
+	}
+
+	return "", nil, fmt.Errorf("unknown error selector 0x%s", selector)
+}
+
+// Indexed dynamic values (string, bytes, arrays) are stored as their keccak
+// hash, so DecodeEventLog returns the raw topic for them.
+func DecodeEventLog(event ABIEvent, log Event) (map[string]interface{}, error) {
+	if len(log.Topics) == 0 || !strings.EqualFold(log.Topics[0], event.Topic()) {
+		return nil, fmt.Errorf("log is not a %s event", event.Name)
+	}
+
+	var indexed, dataParams []ABIParam
+	for _, input := range event.Inputs {
+		if input.Indexed {
+			indexed = append(indexed, input)
+		} else {
+			dataParams = append(dataParams, input)
+		}
+	}
+	if len(log.Topics) != len(indexed)+1 {
+		return nil, fmt.Errorf("%s event expects %d topics, got %d", event.Name, len(indexed)+1, len(log.Topics))
+	}
+
+	values := make(map[string]interface{}, len(event.Inputs))
+	paramName := func(param ABIParam, i int) string {
+		if param.Name != "" {
+			return param.Name
+		}
+		return fmt.Sprintf("param%d", i)
+	}
+
+	var dataValues []interface{}
+	if len(dataParams) > 0 {
+		dataTypes := make([]string, len(dataParams))
+		for i, param := range dataParams {
+			dataTypes[i] = param.canonicalType()
+		}
+
+		data, err := normalizeHex(log.Data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid log data: %w", err)
+		}
+		dataValues, err = DecodeFunctionResult(dataTypes, data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s data: %w", event.Name, err)
+		}
+	}
+
+	topicIndex := 1
+	dataIndex := 0
+	for i, input := range event.Inputs {
+		if !input.Indexed {
+			values[paramName(input, i)] = dataValues[dataIndex]
+			dataIndex++
+			continue
+		}
+
+		topic := log.Topics[topicIndex]
+		topicIndex++
+		abiType := input.canonicalType()
+		if isDynamicType(abiType) || strings.HasSuffix(abiType, "]") || strings.HasPrefix(abiType, "(") {
+			values[paramName(input, i)] = topic
+			continue
+		}
+
+		raw, err := normalizeHex(topic)
+		if err != nil || len(raw) != 32 {
+			return nil, fmt.Errorf("invalid topic for %s", paramName(input, i))
+		}
+		value, _, err := decodeValue(abiType, raw, 0, false)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode indexed %s: %w", paramName(input, i), err)
+		}
+		values[paramName(input, i)] = value
+	}
+
+	return values, nil
+}
+
+func decodeValue(abiType string, data []byte, offset int, strict bool) (interface{}, int, error) {
 	switch {
+	case strings.HasSuffix(abiType, "[]"):
+		return decodeArray(abiType, data, offset, strict)
+	case strings.HasSuffix(abiType, "]"):
+		return decodeFixedArray(abiType, data, offset, strict)
+	case strings.HasPrefix(abiType, "("):
+		return decodeTuple(abiType, data, offset, strict)
 	case abiType == "address":
-		return decodeAddress(data, offset)
+		return decodeAddress(data, offset, strict)
 	case strings.HasPrefix(abiType, "uint"):
 		return decodeUint(data, offset)
+	case strings.HasPrefix(abiType, "int"):
+		return decodeInt(data, offset)
 	case abiType == "bool":
 		return decodeBool(data, offset)
 	case abiType == "string":
 		return decodeString(data, offset)
+	case abiType == "bytes":
+		return decodeBytes(data, offset)
+	case isFixedBytesType(abiType):
+		return decodeFixedBytes(abiType, data, offset)
 	default:
 		return nil, 0, fmt.Errorf("unsupported decode type: %s", abiType)
 	}
 }
 
-func decodeAddress(data []byte, offset int) (string, int, error) {
+func decodeAddress(data []byte, offset int, strict bool) (string, int, error) {
 	if offset+32 > len(data) {
 		return "", 0, fmt.Errorf("insufficient data for address")
 	}
 
+	if strict {
+		for _, b := range data[offset : offset+12] {
+			if b != 0 {
+				return "", 0, fmt.Errorf("address word has non-zero upper bytes")
+			}
+		}
+	}
+
 	addressBytes := data[offset+12 : offset+32]
 	address := "0x" + hex.EncodeToString(addressBytes)
 
@@ -327,6 +774,18 @@ func decodeUint(data []byte, offset int) (*big.Int, int, error) {
 	return value, offset + 32, nil
 }
 
+func decodeInt(data []byte, offset int) (*big.Int, int, error) {
+	if offset+32 > len(data) {
+		return nil, 0, fmt.Errorf("insufficient data for int")
+	}
+
+	value := new(big.Int).SetBytes(data[offset : offset+32])
+	if data[offset]&0x80 != 0 {
+		value.Sub(value, new(big.Int).Lsh(big.NewInt(1), 256))
+	}
+	return value, offset + 32, nil
+}
+
 func decodeBool(data []byte, offset int) (bool, int, error) {
 	if offset+32 > len(data) {
 		return false, 0, fmt.Errorf("insufficient data for bool")
@@ -337,47 +796,474 @@ func decodeBool(data []byte, offset int) (bool, int, error) {
 }
 
 func decodeString(data []byte, offset int) (string, int, error) {
+	value, next, err := decodeBytes(data, offset)
+	if err != nil {
+		return "", 0, err
+	}
+	return string(value), next, nil
+}
+
+func decodeBytes(data []byte, offset int) ([]byte, int, error) {
 	if offset+32 > len(data) {
-		return "", 0, fmt.Errorf("insufficient data for string offset")
+		return nil, 0, fmt.Errorf("insufficient data for dynamic offset")
 	}
 
-	stringOffset := new(big.Int).SetBytes(data[offset : offset+32]).Int64()
-	if int(stringOffset)+32 > len(data) {
-		return "", 0, fmt.Errorf("insufficient data for string length")
+	dataOffset := new(big.Int).SetBytes(data[offset : offset+32])
+	if !dataOffset.IsInt64() || dataOffset.Int64() > int64(len(data)-32) {
+		return nil, 0, fmt.Errorf("insufficient data for dynamic length")
 	}
+	start := int(dataOffset.Int64())
 
-	length := new(big.Int).SetBytes(data[stringOffset : stringOffset+32]).Int64()
-	if int(stringOffset)+32+int(length) > len(data) {
-		return "", 0, fmt.Errorf("insufficient data for string content")
+	length := new(big.Int).SetBytes(data[start : start+32])
+	if !length.IsInt64() || length.Int64() > int64(len(data)-start-32) {
+		return nil, 0, fmt.Errorf("insufficient data for dynamic content")
 	}
 
-	stringBytes := data[stringOffset+32 : stringOffset+32+length]
-	return string(stringBytes), offset + 32, nil
+	value := make([]byte, length.Int64())
+	copy(value, data[start+32:])
+	return value, offset + 32, nil
 }
 
-func ParseABISignature(signature string) (*ABIFunction, error) {
-	re := regexp.MustCompile(`^(\w+)\((.*)\)$`)
-	matches := re.FindStringSubmatch(signature)
-	if len(matches) != 3 {
-		return nil, fmt.Errorf("invalid function signature format")
+func decodeArray(abiType string, data []byte, offset int, strict bool) ([]interface{}, int, error) {
+	elementType := strings.TrimSuffix(abiType, "[]")
+
+	if offset+32 > len(data) {
+		return nil, 0, fmt.Errorf("insufficient data for array offset")
 	}
 
-	funcName := matches[1]
-	paramStr := matches[2]
+	arrayOffset := new(big.Int).SetBytes(data[offset : offset+32])
+	if !arrayOffset.IsInt64() || arrayOffset.Int64() > int64(len(data)-32) {
+		return nil, 0, fmt.Errorf("insufficient data for array length")
+	}
+	start := int(arrayOffset.Int64())
+
+	length := new(big.Int).SetBytes(data[start : start+32])
+	elementData := data[start+32:]
+	// Every element occupies at least one head word, which bounds the count
+	// without multiplying an attacker-controlled length.
+	if !length.IsInt64() || length.Int64() > int64(len(elementData)/32) {
+		return nil, 0, fmt.Errorf("insufficient data for array elements")
+	}
 
-	var params []ABIParam
-	if paramStr != "" {
-		paramTypes := strings.Split(paramStr, ",")
-		for i, paramType := range paramTypes {
-			params = append(params, ABIParam{
-				Name: fmt.Sprintf("param%d", i),
-				Type: strings.TrimSpace(paramType),
-			})
+	count := int(length.Int64())
+	elements := make([]interface{}, 0, count)
+	elementOffset := 0
+	for i := 0; i < count; i++ {
+		element, next, err := decodeValue(elementType, elementData, elementOffset, strict)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to decode array element %d: %w", i, err)
 		}
+		elements = append(elements, element)
+		elementOffset = next
+	}
+
+	return elements, offset + 32, nil
+}
+
+func decodeFixedArray(abiType string, data []byte, offset int, strict bool) ([]interface{}, int, error) {
+	elementType, size, ok := parseFixedArrayType(abiType)
+	if !ok {
+		return nil, 0, fmt.Errorf("invalid fixed array type: %s", abiType)
+	}
+
+	elementData := data
+	elementOffset := offset
+	if isDynamicType(elementType) {
+		if offset+32 > len(data) {
+			return nil, 0, fmt.Errorf("insufficient data for array offset")
+		}
+		arrayOffset := new(big.Int).SetBytes(data[offset : offset+32])
+		if !arrayOffset.IsInt64() || arrayOffset.Int64() > int64(len(data)) {
+			return nil, 0, fmt.Errorf("insufficient data for array elements")
+		}
+		elementData = data[arrayOffset.Int64():]
+		elementOffset = 0
+	}
+
+	elements := make([]interface{}, 0, size)
+	for i := 0; i < size; i++ {
+		element, next, err := decodeValue(elementType, elementData, elementOffset, strict)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to decode array element %d: %w", i, err)
+		}
+		elements = append(elements, element)
+		elementOffset = next
+	}
+
+	if isDynamicType(elementType) {
+		return elements, offset + 32, nil
+	}
+	return elements, elementOffset, nil
+}
+
+func decodeTuple(abiType string, data []byte, offset int, strict bool) ([]interface{}, int, error) {
+	components, ok := tupleComponentTypes(abiType)
+	if !ok {
+		return nil, 0, fmt.Errorf("invalid tuple type: %s", abiType)
+	}
+
+	tupleData := data
+	componentOffset := offset
+	dynamic := isDynamicType(abiType)
+	if dynamic {
+		if offset+32 > len(data) {
+			return nil, 0, fmt.Errorf("insufficient data for tuple offset")
+		}
+		tupleOffset := new(big.Int).SetBytes(data[offset : offset+32])
+		if !tupleOffset.IsInt64() || tupleOffset.Int64() > int64(len(data)) {
+			return nil, 0, fmt.Errorf("insufficient data for tuple")
+		}
+		tupleData = data[tupleOffset.Int64():]
+		componentOffset = 0
+	}
+
+	values := make([]interface{}, 0, len(components))
+	for i, component := range components {
+		value, next, err := decodeValue(component, tupleData, componentOffset, strict)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to decode tuple component %d: %w", i, err)
+		}
+		values = append(values, value)
+		componentOffset = next
+	}
+
+	if dynamic {
+		return values, offset + 32, nil
+	}
+	return values, componentOffset, nil
+}
+
+func decodeFixedBytes(abiType string, data []byte, offset int) ([]byte, int, error) {
+	size, err := fixedBytesSize(abiType)
+	if err != nil {
+		return nil, 0, err
+	}
+	if offset+32 > len(data) {
+		return nil, 0, fmt.Errorf("insufficient data for %s", abiType)
+	}
+
+	for _, b := range data[offset+size : offset+32] {
+		if b != 0 {
+			return nil, 0, fmt.Errorf("non-zero padding in %s value", abiType)
+		}
+	}
+
+	value := make([]byte, size)
+	copy(value, data[offset:offset+size])
+	return value, offset + 32, nil
+}
+
+func ParseABISignature(signature string) (*ABIFunction, error) {
+	name, inputs, rest, err := splitABISignature(signature, "function")
+	if err != nil {
+		return nil, fmt.Errorf("invalid function signature format: %w", err)
+	}
+
+	params, err := parseABIParams(inputs, false)
+	if err != nil {
+		return nil, err
+	}
+	outputs, err := parseFunctionSuffix(rest)
+	if err != nil {
+		return nil, err
 	}
 
 	return &ABIFunction{
-		Name:   funcName,
+		Name:    name,
+		Inputs:  params,
+		Outputs: outputs,
+	}, nil
+}
+
+func ParseEventSignature(signature string) (*ABIEvent, error) {
+	name, inputs, rest, err := splitABISignature(signature, "event")
+	if err != nil {
+		return nil, fmt.Errorf("invalid event signature format: %w", err)
+	}
+	if rest != "" {
+		return nil, fmt.Errorf("unexpected %q after event parameters", rest)
+	}
+
+	params, err := parseABIParams(inputs, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ABIEvent{
+		Name:   name,
 		Inputs: params,
 	}, nil
 }
+
+var (
+	abiNamePattern = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+	abiTypePattern = regexp.MustCompile(`^[a-z]+[0-9]*(x[0-9]+)?$`)
+)
+
+func splitABISignature(signature, keyword string) (string, string, string, error) {
+	s := strings.TrimSpace(signature)
+	s = strings.TrimSpace(strings.TrimPrefix(s, keyword+" "))
+
+	open := strings.IndexByte(s, '(')
+	if open < 0 {
+		return "", "", "", fmt.Errorf("missing parameter list")
+	}
+	name := strings.TrimSpace(s[:open])
+	if !abiNamePattern.MatchString(name) {
+		return "", "", "", fmt.Errorf("invalid name %q", name)
+	}
+
+	end := matchingParen(s, open)
+	if end < 0 {
+		return "", "", "", fmt.Errorf("unbalanced parentheses")
+	}
+
+	return name, s[open+1 : end], strings.TrimSpace(s[end+1:]), nil
+}
+
+// parseFunctionSuffix accepts the human-readable ABI tail, e.g.
+// "view returns (uint256 balance)", and returns the declared outputs.
+func parseFunctionSuffix(rest string) ([]ABIParam, error) {
+	for rest != "" {
+		if strings.HasPrefix(rest, "returns") {
+			list := strings.TrimSpace(strings.TrimPrefix(rest, "returns"))
+			if !strings.HasPrefix(list, "(") || matchingParen(list, 0) != len(list)-1 {
+				return nil, fmt.Errorf("invalid returns clause %q", rest)
+			}
+			return parseABIParams(list[1:len(list)-1], false)
+		}
+
+		word := strings.Fields(rest)[0]
+		switch word {
+		case "view", "pure", "payable", "nonpayable", "external", "public":
+		default:
+			return nil, fmt.Errorf("unexpected %q after function parameters", word)
+		}
+		rest = strings.TrimSpace(strings.TrimPrefix(rest, word))
+	}
+	return nil, nil
+}
+
+func parseABIParams(list string, allowIndexed bool) ([]ABIParam, error) {
+	if strings.TrimSpace(list) == "" {
+		return nil, nil
+	}
+
+	fields, err := splitABIList(list)
+	if err != nil {
+		return nil, err
+	}
+
+	params := make([]ABIParam, 0, len(fields))
+	for i, field := range fields {
+		param, err := parseABIParam(field, i, allowIndexed)
+		if err != nil {
+			return nil, err
+		}
+		params = append(params, param)
+	}
+	return params, nil
+}
+
+func parseABIParam(field string, index int, allowIndexed bool) (ABIParam, error) {
+	field = strings.TrimSpace(field)
+	if field == "" {
+		return ABIParam{}, fmt.Errorf("empty parameter at index %d", index)
+	}
+
+	abiType, components, rest, err := parseABIType(field)
+	if err != nil {
+		return ABIParam{}, fmt.Errorf("invalid parameter %q: %w", field, err)
+	}
+
+	param := ABIParam{
+		Name:       fmt.Sprintf("param%d", index),
+		Type:       abiType,
+		Components: components,
+	}
+
+	var names []string
+	for _, token := range strings.Fields(rest) {
+		switch token {
+		case "indexed":
+			if !allowIndexed || param.Indexed || len(names) > 0 {
+				return ABIParam{}, fmt.Errorf("unexpected indexed in parameter %q", field)
+			}
+			param.Indexed = true
+		case "memory", "calldata", "storage":
+		default:
+			names = append(names, token)
+		}
+	}
+	switch len(names) {
+	case 0:
+	case 1:
+		if !abiNamePattern.MatchString(names[0]) {
+			return ABIParam{}, fmt.Errorf("invalid parameter name %q", names[0])
+		}
+		param.Name = names[0]
+	default:
+		return ABIParam{}, fmt.Errorf("invalid parameter %q", field)
+	}
+
+	return param, nil
+}
+
+// parseABIType reads the leading type of a parameter and returns it in
+// canonical form, its tuple components and the unparsed remainder.
+func parseABIType(field string) (string, []ABIParam, string, error) {
+	var abiType string
+	var components []ABIParam
+	pos := 0
+
+	if strings.HasPrefix(field, "tuple(") {
+		field = field[len("tuple"):]
+	}
+	if strings.HasPrefix(field, "(") {
+		end := matchingParen(field, 0)
+		if end < 0 {
+			return "", nil, "", fmt.Errorf("unbalanced parentheses")
+		}
+		var err error
+		components, err = parseABIParams(field[1:end], false)
+		if err != nil {
+			return "", nil, "", err
+		}
+		types := make([]string, len(components))
+		for i, component := range components {
+			types[i] = component.Type
+		}
+		abiType = "(" + strings.Join(types, ",") + ")"
+		pos = end + 1
+	} else {
+		for pos < len(field) && field[pos] != '[' && field[pos] != ' ' && field[pos] != '\t' {
+			pos++
+		}
+		abiType = canonicalABIType(field[:pos])
+		if !abiTypePattern.MatchString(abiType) {
+			return "", nil, "", fmt.Errorf("invalid type %q", field[:pos])
+		}
+	}
+
+	for pos < len(field) && field[pos] == '[' {
+		end := strings.IndexByte(field[pos:], ']')
+		if end < 0 {
+			return "", nil, "", fmt.Errorf("unterminated array suffix")
+		}
+		size := field[pos+1 : pos+end]
+		if size != "" {
+			if _, err := strconv.Atoi(size); err != nil {
+				return "", nil, "", fmt.Errorf("invalid array size %q", size)
+			}
+		}
+		abiType += field[pos : pos+end+1]
+		pos += end + 1
+	}
+
+	if pos < len(field) && field[pos] != ' ' && field[pos] != '\t' {
+		return "", nil, "", fmt.Errorf("unexpected %q after type", field[pos:])
+	}
+	return abiType, components, field[pos:], nil
+}
+
+func canonicalABIType(abiType string) string {
+	switch abiType {
+	case "uint":
+		return "uint256"
+	case "int":
+		return "int256"
+	case "byte":
+		return "bytes1"
+	}
+	return abiType
+}
+
+// splitABIList splits on top-level commas only, so tuple components and
+// array sizes stay with their parameter.
+func splitABIList(list string) ([]string, error) {
+	var fields []string
+	depth := 0
+	start := 0
+	for i, c := range list {
+		switch c {
+		case '(', '[':
+			depth++
+		case ')', ']':
+			depth--
+			if depth < 0 {
+				return nil, fmt.Errorf("unbalanced brackets in %q", list)
+			}
+		case ',':
+			if depth == 0 {
+				fields = append(fields, list[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("unbalanced brackets in %q", list)
+	}
+	return append(fields, list[start:]), nil
+}
+
+func matchingParen(s string, open int) int {
+	depth := 0
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func (event ABIEvent) Signature() string {
+	types := make([]string, len(event.Inputs))
+	for i, input := range event.Inputs {
+		types[i] = input.canonicalType()
+	}
+	return event.Name + "(" + strings.Join(types, ",") + ")"
+}
+
+func (event ABIEvent) Topic() string {
+	return "0x" + Keccak256([]byte(event.Signature()))
+}
+
+func EncodeStruct(value interface{}) ([]byte, error) {
+	rv := reflect.ValueOf(value)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, fmt.Errorf("struct value is nil")
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("value must be a struct, got %s", rv.Kind())
+	}
+
+	rt := rv.Type()
+	var params []ABIParam
+	var values []interface{}
+
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		abiType, ok := field.Tag.Lookup("abi")
+		if !ok || abiType == "" {
+			return nil, fmt.Errorf("field %s has no abi tag", field.Name)
+		}
+
+		params = append(params, ABIParam{Name: field.Name, Type: abiType})
+		values = append(values, rv.Field(i).Interface())
+	}
+
+	return encodeParameters(params, values)
+}
@@ -0,0 +1,579 @@
+package web3
+
+import (
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestEncodeDynamicStringArray(t *testing.T) {
+	encoded, err := encodeParameters([]ABIParam{{Type: "string[]"}}, []interface{}{[]string{"a", "bb"}})
+	if err != nil {
+		t.Fatalf("encodeParameters failed: %v", err)
+	}
+
+	want := strings.Join([]string{
+		"0000000000000000000000000000000000000000000000000000000000000020",
+		"0000000000000000000000000000000000000000000000000000000000000002",
+		"0000000000000000000000000000000000000000000000000000000000000040",
+		"0000000000000000000000000000000000000000000000000000000000000080",
+		"0000000000000000000000000000000000000000000000000000000000000001",
+		"6100000000000000000000000000000000000000000000000000000000000000",
+		"0000000000000000000000000000000000000000000000000000000000000002",
+		"6262000000000000000000000000000000000000000000000000000000000000",
+	}, "")
+	if got := hex.EncodeToString(encoded); got != want {
+		t.Errorf("encoding = %s, want %s", got, want)
+	}
+}
+
+func TestEncodeFixedBytes(t *testing.T) {
+	hash := keccak256([]byte("hello"))
+	encoded, err := encodeValue("bytes32", "0x"+hex.EncodeToString(hash))
+	if err != nil {
+		t.Fatalf("bytes32 encode failed: %v", err)
+	}
+	if hex.EncodeToString(encoded) != hex.EncodeToString(hash) {
+		t.Errorf("bytes32 = %x, want %x", encoded, hash)
+	}
+
+	encoded, err = encodeValue("bytes4", "0xa9059cbb")
+	if err != nil {
+		t.Fatalf("bytes4 encode failed: %v", err)
+	}
+	want := "a9059cbb00000000000000000000000000000000000000000000000000000000"
+	if got := hex.EncodeToString(encoded); got != want {
+		t.Errorf("bytes4 = %s, want %s", got, want)
+	}
+
+	for _, input := range []string{"0xa9059c", "0xa9059cbb00", "0x" + hex.EncodeToString(hash)} {
+		if _, err := encodeValue("bytes4", input); err == nil {
+			t.Errorf("bytes4 accepted %s", input)
+		}
+	}
+}
+
+func TestDecodeFixedBytesReturnValues(t *testing.T) {
+	hash := keccak256([]byte("hello"))
+	data, _ := hex.DecodeString(hex.EncodeToString(hash) + "a9059cbb00000000000000000000000000000000000000000000000000000000")
+
+	values, err := DecodeFunctionResult([]string{"bytes32", "bytes4"}, data)
+	if err != nil {
+		t.Fatalf("DecodeFunctionResult failed: %v", err)
+	}
+	if got := hex.EncodeToString(values[0].([]byte)); got != hex.EncodeToString(hash) {
+		t.Errorf("bytes32 = %s, want %x", got, hash)
+	}
+	if got := hex.EncodeToString(values[1].([]byte)); got != "a9059cbb" {
+		t.Errorf("bytes4 = %s, want a9059cbb", got)
+	}
+
+	dirty, _ := hex.DecodeString("a9059cbb00000000000000000000000000000000000000000000000000000001")
+	if _, err := DecodeFunctionResult([]string{"bytes4"}, dirty); err == nil {
+		t.Error("expected error for non-zero bytes4 padding")
+	}
+}
+
+func TestEncodeFixedArrayInline(t *testing.T) {
+	if isDynamicType("uint8[3]") {
+		t.Fatal("uint8[3] reported as dynamic")
+	}
+
+	encoded, err := encodeParameters([]ABIParam{{Type: "uint8[3]"}}, []interface{}{[]interface{}{1, 2, 3}})
+	if err != nil {
+		t.Fatalf("encodeParameters failed: %v", err)
+	}
+	if len(encoded) != 96 {
+		t.Fatalf("uint8[3] occupies %d bytes, want 96", len(encoded))
+	}
+	for i := 0; i < 3; i++ {
+		if encoded[i*32+31] != byte(i+1) {
+			t.Errorf("element %d = %x, want %d with no length word", i, encoded[i*32:(i+1)*32], i+1)
+		}
+	}
+
+	values, err := DecodeFunctionResult([]string{"uint8[3]"}, encoded)
+	if err != nil {
+		t.Fatalf("DecodeFunctionResult failed: %v", err)
+	}
+	if elements := values[0].([]interface{}); len(elements) != 3 {
+		t.Errorf("decoded %d elements, want 3", len(elements))
+	}
+
+	if _, err := encodeValue("uint8[3]", []interface{}{1, 2}); err == nil {
+		t.Error("expected error for wrong element count")
+	}
+}
+
+func TestEncodeStructMatchesTuple(t *testing.T) {
+	type transfer struct {
+		To     string   `abi:"address"`
+		Amount *big.Int `abi:"uint256"`
+		Memo   string   `abi:"string"`
+		note   string
+	}
+
+	value := transfer{To: testSpender, Amount: big.NewInt(1000), Memo: "rent", note: "ignored"}
+	encoded, err := EncodeStruct(&value)
+	if err != nil {
+		t.Fatalf("EncodeStruct failed: %v", err)
+	}
+
+	tuple, err := encodeValue("(address,uint256,string)", []interface{}{value.To, value.Amount, value.Memo})
+	if err != nil {
+		t.Fatalf("tuple encode failed: %v", err)
+	}
+	if hex.EncodeToString(encoded) != hex.EncodeToString(tuple) {
+		t.Errorf("EncodeStruct = %x, want %x", encoded, tuple)
+	}
+
+	type untagged struct {
+		Amount *big.Int
+	}
+	if _, err := EncodeStruct(untagged{Amount: big.NewInt(1)}); err == nil {
+		t.Error("expected error for field without abi tag")
+	}
+}
+
+func TestDecodeCustomError(t *testing.T) {
+	// Revert data for InsufficientBalance(100, 250), as in the Solidity docs example.
+	data, _ := hex.DecodeString("cf479181" +
+		"0000000000000000000000000000000000000000000000000000000000000064" +
+		"00000000000000000000000000000000000000000000000000000000000000fa")
+
+	defs := []ABIFunction{
+		{Name: "Unauthorized"},
+		{Name: "InsufficientBalance", Inputs: []ABIParam{{Name: "available", Type: "uint256"}, {Name: "required", Type: "uint256"}}},
+	}
+
+	name, args, err := DecodeCustomError(data, defs)
+	if err != nil {
+		t.Fatalf("DecodeCustomError failed: %v", err)
+	}
+	if name != "InsufficientBalance" {
+		t.Errorf("name = %s, want InsufficientBalance", name)
+	}
+	if len(args) != 2 || args[0].(*big.Int).Int64() != 100 || args[1].(*big.Int).Int64() != 250 {
+		t.Errorf("args = %v, want [100 250]", args)
+	}
+
+	if _, _, err := DecodeCustomError(data, defs[:1]); err == nil {
+		t.Error("expected error for unknown selector")
+	}
+}
+
+func TestExtendedSelector(t *testing.T) {
+	selector, err := ExtendedSelector("transfer(address,uint256)", 4)
+	if err != nil {
+		t.Fatalf("ExtendedSelector failed: %v", err)
+	}
+	if got := hex.EncodeToString(selector); got != "a9059cbb" {
+		t.Errorf("4-byte selector = %s, want a9059cbb", got)
+	}
+
+	extended, err := ExtendedSelector("transfer(address,uint256)", 8)
+	if err != nil {
+		t.Fatalf("ExtendedSelector failed: %v", err)
+	}
+	if got := hex.EncodeToString(extended); got != "a9059cbb2ab09eb2" {
+		t.Errorf("8-byte selector = %s, want a9059cbb2ab09eb2", got)
+	}
+
+	for _, size := range []int{0, 33} {
+		if _, err := ExtendedSelector("transfer(address,uint256)", size); err == nil {
+			t.Errorf("ExtendedSelector accepted %d bytes", size)
+		}
+	}
+}
+
+func TestSignedIntArrayRoundTrip(t *testing.T) {
+	values := []interface{}{big.NewInt(-1), big.NewInt(42), new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), 255))}
+
+	encoded, err := encodeParameters([]ABIParam{{Type: "int256[]"}}, []interface{}{values})
+	if err != nil {
+		t.Fatalf("encodeParameters failed: %v", err)
+	}
+	if got := hex.EncodeToString(encoded[64:96]); got != strings.Repeat("ff", 32) {
+		t.Errorf("-1 encoded as %s, want two's complement", got)
+	}
+
+	decoded, err := DecodeFunctionResult([]string{"int256[]"}, encoded)
+	if err != nil {
+		t.Fatalf("DecodeFunctionResult failed: %v", err)
+	}
+	elements := decoded[0].([]interface{})
+	if len(elements) != len(values) {
+		t.Fatalf("decoded %d elements, want %d", len(elements), len(values))
+	}
+	for i, element := range elements {
+		if element.(*big.Int).Cmp(values[i].(*big.Int)) != 0 {
+			t.Errorf("element %d = %s, want %s", i, element, values[i])
+		}
+	}
+
+	narrow, err := encodeParameters([]ABIParam{{Type: "int8[]"}}, []interface{}{[]interface{}{-128, 127}})
+	if err != nil {
+		t.Fatalf("int8[] encode failed: %v", err)
+	}
+	decoded, err = DecodeFunctionResult([]string{"int8[]"}, narrow)
+	if err != nil {
+		t.Fatalf("int8[] decode failed: %v", err)
+	}
+	if elements := decoded[0].([]interface{}); elements[0].(*big.Int).Int64() != -128 || elements[1].(*big.Int).Int64() != 127 {
+		t.Errorf("int8[] = %v, want [-128 127]", elements)
+	}
+	if _, err := encodeValue("int8[]", []interface{}{128}); err == nil {
+		t.Error("expected error for int8 overflow")
+	}
+}
+
+func TestDecodeAddressStrict(t *testing.T) {
+	clean, _ := hex.DecodeString("000000000000000000000000" + strings.ToLower(testOwner[2:]))
+	dirty, _ := hex.DecodeString("ff0000000000000000000000" + strings.ToLower(testOwner[2:]))
+
+	values, err := DecodeFunctionResultStrict([]string{"address"}, clean)
+	if err != nil {
+		t.Fatalf("strict decode of clean word failed: %v", err)
+	}
+	if !strings.EqualFold(values[0].(string), testOwner) {
+		t.Errorf("address = %s, want %s", values[0], testOwner)
+	}
+
+	if _, err := DecodeFunctionResultStrict([]string{"address"}, dirty); err == nil {
+		t.Error("strict decode accepted dirty high bytes")
+	}
+	if _, err := DecodeFunctionResultStrict([]string{"address[]"}, append(append(
+		mustDecodeHex(t, "0000000000000000000000000000000000000000000000000000000000000020"),
+		mustDecodeHex(t, "0000000000000000000000000000000000000000000000000000000000000001")...), dirty...)); err == nil {
+		t.Error("strict decode accepted dirty high bytes inside an array")
+	}
+	if _, err := DecodeFunctionResult([]string{"address"}, dirty); err != nil {
+		t.Errorf("lenient decode rejected dirty high bytes: %v", err)
+	}
+}
+
+func mustDecodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	data, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatalf("invalid test hex %q", s)
+	}
+	return data
+}
+
+func TestDecodeEventLogTransfer(t *testing.T) {
+	event := ABIEvent{
+		Name: "Transfer",
+		Inputs: []ABIParam{
+			{Name: "from", Type: "address", Indexed: true},
+			{Name: "to", Type: "address", Indexed: true},
+			{Name: "value", Type: "uint256"},
+		},
+	}
+	if event.Topic() != ERC20_TRANSFER_SIGNATURE {
+		t.Fatalf("topic = %s, want %s", event.Topic(), ERC20_TRANSFER_SIGNATURE)
+	}
+
+	values, err := DecodeEventLog(event, transferLog(testTokenAddress))
+	if err != nil {
+		t.Fatalf("DecodeEventLog failed: %v", err)
+	}
+	if len(values) != 3 {
+		t.Fatalf("got %d values, want 3: %v", len(values), values)
+	}
+	if from, _ := values["from"].(string); !strings.EqualFold(from, testOwner) {
+		t.Errorf("from = %v, want %s", values["from"], testOwner)
+	}
+	if to, _ := values["to"].(string); !strings.EqualFold(to, testSpender) {
+		t.Errorf("to = %v, want %s", values["to"], testSpender)
+	}
+	if value, ok := values["value"].(*big.Int); !ok || value.Int64() != 1_000_000 {
+		t.Errorf("value = %v, want 1000000", values["value"])
+	}
+
+	approval := transferLog(testTokenAddress)
+	approval.Topics[0] = ERC20_APPROVAL_SIGNATURE
+	if _, err := DecodeEventLog(event, approval); err == nil {
+		t.Error("expected error for a log with a different topic 0")
+	}
+	missing := transferLog(testTokenAddress)
+	missing.Topics = missing.Topics[:2]
+	if _, err := DecodeEventLog(event, missing); err == nil {
+		t.Error("expected error for a log missing an indexed topic")
+	}
+}
+
+func TestParseEventSignatureMixedIndexed(t *testing.T) {
+	event, err := ParseEventSignature("event Swap(address indexed sender, uint amount0In, uint amount1In, uint amount0Out, uint amount1Out, address indexed to)")
+	if err != nil {
+		t.Fatalf("ParseEventSignature failed: %v", err)
+	}
+	if event.Name != "Swap" || len(event.Inputs) != 6 {
+		t.Fatalf("event = %+v", event)
+	}
+
+	wantIndexed := []bool{true, false, false, false, false, true}
+	wantNames := []string{"sender", "amount0In", "amount1In", "amount0Out", "amount1Out", "to"}
+	for i, input := range event.Inputs {
+		if input.Indexed != wantIndexed[i] || input.Name != wantNames[i] {
+			t.Errorf("input %d = %+v, want name %s indexed %v", i, input, wantNames[i], wantIndexed[i])
+		}
+	}
+
+	if event.Signature() != "Swap(address,uint256,uint256,uint256,uint256,address)" {
+		t.Errorf("signature = %s", event.Signature())
+	}
+	// Uniswap V2 Pair Swap topic.
+	if event.Topic() != "0xd78ad95fa46c994b6551d0da85fc275fe613ce37657fb8d5e3d130840159d822" {
+		t.Errorf("topic = %s", event.Topic())
+	}
+
+	if _, err := ParseEventSignature("event Bad(address indexed indexed from)"); err == nil {
+		t.Error("expected error for a repeated indexed keyword")
+	}
+}
+
+func TestParseABISignatureNamedParams(t *testing.T) {
+	fn, err := ParseABISignature("function transfer(address to, uint256 amount) external returns (bool success)")
+	if err != nil {
+		t.Fatalf("ParseABISignature failed: %v", err)
+	}
+	if fn.Name != "transfer" || len(fn.Inputs) != 2 || len(fn.Outputs) != 1 {
+		t.Fatalf("function = %+v", fn)
+	}
+	if fn.Inputs[0].Name != "to" || fn.Inputs[0].Type != "address" || fn.Inputs[1].Name != "amount" || fn.Inputs[1].Type != "uint256" {
+		t.Errorf("inputs = %+v", fn.Inputs)
+	}
+	if fn.Outputs[0].Name != "success" || fn.Outputs[0].Type != "bool" {
+		t.Errorf("outputs = %+v", fn.Outputs)
+	}
+	if sig := createFunctionSignature(fn.Name, fn.Inputs); sig != "transfer(address,uint256)" {
+		t.Errorf("signature = %s", sig)
+	}
+
+	unnamed, err := ParseABISignature("transfer(address,uint256)")
+	if err != nil {
+		t.Fatalf("ParseABISignature failed: %v", err)
+	}
+	if unnamed.Inputs[0].Name != "param0" || unnamed.Inputs[1].Name != "param1" {
+		t.Errorf("unnamed inputs = %+v", unnamed.Inputs)
+	}
+}
+
+func TestParseABISignatureTuple(t *testing.T) {
+	fn, err := ParseABISignature("function exactInputSingle((address tokenIn, address tokenOut, uint24 fee, address recipient, uint256 deadline, uint256 amountIn, uint256 amountOutMinimum, uint160 sqrtPriceLimitX96) params) payable returns (uint256 amountOut)")
+	if err != nil {
+		t.Fatalf("ParseABISignature failed: %v", err)
+	}
+	if len(fn.Inputs) != 1 || fn.Inputs[0].Name != "params" || len(fn.Inputs[0].Components) != 8 {
+		t.Fatalf("inputs = %+v", fn.Inputs)
+	}
+	if fn.Inputs[0].Components[2].Name != "fee" || fn.Inputs[0].Components[2].Type != "uint24" {
+		t.Errorf("component 2 = %+v", fn.Inputs[0].Components[2])
+	}
+
+	sig := createFunctionSignature(fn.Name, fn.Inputs)
+	if sig != "exactInputSingle((address,address,uint24,address,uint256,uint256,uint256,uint160))" {
+		t.Errorf("signature = %s", sig)
+	}
+	// Uniswap V3 SwapRouter.exactInputSingle
+	if selector := Keccak256([]byte(sig))[:8]; selector != "414bf389" {
+		t.Errorf("selector = %s, want 414bf389", selector)
+	}
+}
+
+func TestParseABISignatureArrays(t *testing.T) {
+	fn, err := ParseABISignature("swapExactTokensForTokens(uint amountIn, uint amountOutMin, address[] calldata path, address to, uint deadline)")
+	if err != nil {
+		t.Fatalf("ParseABISignature failed: %v", err)
+	}
+	if fn.Inputs[2].Name != "path" || fn.Inputs[2].Type != "address[]" {
+		t.Errorf("path input = %+v", fn.Inputs[2])
+	}
+	sig := createFunctionSignature(fn.Name, fn.Inputs)
+	if sig != "swapExactTokensForTokens(uint256,uint256,address[],address,uint256)" {
+		t.Errorf("signature = %s", sig)
+	}
+	if selector := Keccak256([]byte(sig))[:8]; selector != "38ed1739" {
+		t.Errorf("selector = %s, want 38ed1739", selector)
+	}
+
+	nested, err := ParseABISignature("f(uint256[2][] memory pairs, (uint8,bytes)[3] items)")
+	if err != nil {
+		t.Fatalf("ParseABISignature failed: %v", err)
+	}
+	if nested.Inputs[0].Type != "uint256[2][]" || nested.Inputs[1].Type != "(uint8,bytes)[3]" || nested.Inputs[1].Name != "items" {
+		t.Errorf("nested inputs = %+v", nested.Inputs)
+	}
+
+	for _, bad := range []string{"f(uint256[)", "f(address to from)", "f((uint256,address)"} {
+		if _, err := ParseABISignature(bad); err == nil {
+			t.Errorf("ParseABISignature(%q) should fail", bad)
+		}
+	}
+}
+
+func TestParseABISignatureNestedTupleSplit(t *testing.T) {
+	fn, err := ParseABISignature("foo((uint256,uint256),address)")
+	if err != nil {
+		t.Fatalf("ParseABISignature failed: %v", err)
+	}
+	if len(fn.Inputs) != 2 {
+		t.Fatalf("got %d params, want 2: %+v", len(fn.Inputs), fn.Inputs)
+	}
+	if fn.Inputs[0].Type != "(uint256,uint256)" || fn.Inputs[1].Type != "address" {
+		t.Errorf("types = %s, %s", fn.Inputs[0].Type, fn.Inputs[1].Type)
+	}
+
+	fields, err := splitABIList("(uint256,(bool,bytes32[2])),uint8[3],address")
+	if err != nil {
+		t.Fatalf("splitABIList failed: %v", err)
+	}
+	want := []string{"(uint256,(bool,bytes32[2]))", "uint8[3]", "address"}
+	if len(fields) != len(want) {
+		t.Fatalf("fields = %q, want %q", fields, want)
+	}
+	for i := range want {
+		if fields[i] != want[i] {
+			t.Errorf("field %d = %q, want %q", i, fields[i], want[i])
+		}
+	}
+
+	components, ok := tupleComponentTypes("((uint256,uint256),address)")
+	if !ok || len(components) != 2 || components[0] != "(uint256,uint256)" {
+		t.Errorf("tupleComponentTypes = %q, %v", components, ok)
+	}
+
+	if _, err := splitABIList("(uint256,address"); err == nil {
+		t.Error("expected error for unbalanced parentheses")
+	}
+}
+
+func TestTupleUint256StringRoundTrip(t *testing.T) {
+	params := []ABIParam{{Type: "tuple", Components: []ABIParam{{Name: "id", Type: "uint256"}, {Name: "label", Type: "string"}}}}
+	encoded, err := encodeParameters(params, []interface{}{[]interface{}{big.NewInt(1), "hi"}})
+	if err != nil {
+		t.Fatalf("encodeParameters failed: %v", err)
+	}
+
+	// The tuple is dynamic, so the head holds an offset and the tail holds
+	// the components with the string offset relative to the tuple start.
+	want := "0000000000000000000000000000000000000000000000000000000000000020" +
+		"0000000000000000000000000000000000000000000000000000000000000001" +
+		"0000000000000000000000000000000000000000000000000000000000000040" +
+		"0000000000000000000000000000000000000000000000000000000000000002" +
+		"6869000000000000000000000000000000000000000000000000000000000000"
+	if got := hex.EncodeToString(encoded); got != want {
+		t.Fatalf("encoding = %s, want %s", got, want)
+	}
+
+	decoded, err := DecodeFunctionResult([]string{"(uint256,string)"}, encoded)
+	if err != nil {
+		t.Fatalf("DecodeFunctionResult failed: %v", err)
+	}
+	tuple, ok := decoded[0].([]interface{})
+	if !ok || len(tuple) != 2 {
+		t.Fatalf("decoded = %#v", decoded)
+	}
+	if id, ok := tuple[0].(*big.Int); !ok || id.Int64() != 1 {
+		t.Errorf("id = %v, want 1", tuple[0])
+	}
+	if tuple[1] != "hi" {
+		t.Errorf("label = %v, want hi", tuple[1])
+	}
+
+	if _, err := encodeParameters(params, []interface{}{[]interface{}{big.NewInt(1)}}); err == nil {
+		t.Error("expected error for a tuple with a missing component")
+	}
+}
+
+func TestEncodeParametersErrorNamesIndexAndTypes(t *testing.T) {
+	params := []ABIParam{{Type: "uint256"}, {Type: "address"}}
+	_, err := encodeParameters(params, []interface{}{big.NewInt(1), 42})
+	if err == nil {
+		t.Fatal("expected error for an int passed as an address")
+	}
+	for _, want := range []string{"parameter 1", "address", "int"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+
+	_, err = EncodeFunctionCall("set", []ABIParam{{Type: "uint8"}}, []interface{}{big.NewInt(300)})
+	if err == nil || !strings.Contains(err.Error(), "parameter 0 (uint8)") || !strings.Contains(err.Error(), "*big.Int") {
+		t.Errorf("error = %v, want it to name parameter 0, uint8 and *big.Int", err)
+	}
+
+	if _, err := encodeParameters(params, []interface{}{big.NewInt(1)}); err == nil || !strings.Contains(err.Error(), "expected 2, got 1") {
+		t.Errorf("error = %v, want count mismatch", err)
+	}
+}
+
+func TestIntegerWidthBounds(t *testing.T) {
+	encoded, err := encodeValue("uint8", big.NewInt(255))
+	if err != nil {
+		t.Fatalf("uint8(255) failed: %v", err)
+	}
+	if encoded[31] != 0xff || new(big.Int).SetBytes(encoded[:31]).Sign() != 0 {
+		t.Errorf("uint8(255) = %x", encoded)
+	}
+	if _, err := encodeValue("uint8", big.NewInt(256)); err == nil {
+		t.Error("expected uint8(256) to fail")
+	}
+
+	tests := []struct {
+		abiType string
+		value   int64
+		ok      bool
+	}{
+		{"int8", 127, true},
+		{"int8", -128, true},
+		{"int8", 128, false},
+		{"int8", -129, false},
+		{"uint16", 65535, true},
+		{"uint16", 65536, false},
+		{"uint", -1, false},
+	}
+	for _, tt := range tests {
+		_, err := encodeValue(tt.abiType, big.NewInt(tt.value))
+		if (err == nil) != tt.ok {
+			t.Errorf("%s(%d) error = %v, want ok=%v", tt.abiType, tt.value, err, tt.ok)
+		}
+	}
+
+	for _, bad := range []string{"uint7", "uint264", "int0", "uint08"} {
+		if _, err := encodeValue(bad, big.NewInt(1)); err == nil {
+			t.Errorf("expected invalid type %s to fail", bad)
+		}
+	}
+}
+
+func TestEncodeAddressWordMalformed(t *testing.T) {
+	word, err := encodeAddressWord(testOwner)
+	if err != nil {
+		t.Fatalf("encodeAddressWord failed: %v", err)
+	}
+	if hex.EncodeToString(word) != testTopicFrom[2:] {
+		t.Errorf("word = %x, want %s", word, testTopicFrom[2:])
+	}
+
+	for _, bad := range []string{
+		"",
+		"0x",
+		"742d35Cc6634c0532925a3B8d82C28d53e01bcF2",
+		"0x742d35Cc6634c0532925a3B8d82C28d53e01bcF",
+		"0x742d35Cc6634c0532925a3B8d82C28d53e01bcF200",
+		"0x742d35Cc6634c0532925a3B8d82C28d53e01bcGz",
+		" 0x742d35Cc6634c0532925a3B8d82C28d53e01bcF2",
+	} {
+		if _, err := encodeAddressWord(bad); err == nil {
+			t.Errorf("encodeAddressWord(%q) should fail", bad)
+		}
+	}
+
+	_, err = NewERC20Token(testTokenAddress, "", "", 6).EncodeTransferFrom(testOwner, "0x1234", big.NewInt(1))
+	if err == nil || !strings.Contains(err.Error(), "invalid recipient address") {
+		t.Errorf("error = %v, want invalid recipient address", err)
+	}
+}
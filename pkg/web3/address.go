@@ -0,0 +1,122 @@
+package web3
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+func ToChecksumAddress(address string) (string, error) {
+	return checksumAddress(address, "")
+}
+
+func ToChecksumAddressChainID(address string, chainID *big.Int) (string, error) {
+	if chainID == nil {
+		return "", fmt.Errorf("chain id is required")
+	}
+	return checksumAddress(address, chainID.String()+"0x")
+}
+
+func checksumAddress(address, hashPrefix string) (string, error) {
+	if !ValidateAddress(address) {
+		return "", fmt.Errorf("invalid address format")
+	}
+
+	lower := strings.ToLower(address[2:])
+	hash := Keccak256([]byte(hashPrefix + lower))
+
+	result := []byte(lower)
+	for i, c := range result {
+		if c >= 'a' && c <= 'f' && hash[i] >= '8' {
+			result[i] = c - 32
+		}
+	}
+
+	return "0x" + string(result), nil
+}
+
+func IsChecksumAddress(address string) bool {
+	if !ValidateAddress(address) || !isMixedCase(address[2:]) {
+		return false
+	}
+
+	checksummed, err := ToChecksumAddress(address)
+	if err != nil {
+		return false
+	}
+	return checksummed == address
+}
+
+func ValidateAddressChecksum(address string) bool {
+	if !ValidateAddress(address) {
+		return false
+	}
+	if !isMixedCase(address[2:]) {
+		return true
+	}
+	return IsChecksumAddress(address)
+}
+
+func isMixedCase(hexPart string) bool {
+	return strings.ToLower(hexPart) != hexPart && strings.ToUpper(hexPart) != hexPart
+}
+
+func NormalizeAddresses(addrs []string) ([]string, []error) {
+	normalized := make([]string, len(addrs))
+	errs := make([]error, len(addrs))
+
+	for i, addr := range addrs {
+		trimmed := strings.TrimSpace(addr)
+		checksummed, err := ToChecksumAddress(trimmed)
+		if err != nil {
+			errs[i] = fmt.Errorf("address %d (%q): %w", i, addr, err)
+			continue
+		}
+		if isMixedCase(trimmed[2:]) && checksummed != trimmed {
+			errs[i] = fmt.Errorf("address %d (%q): invalid checksum", i, addr)
+			continue
+		}
+		normalized[i] = checksummed
+	}
+
+	return normalized, errs
+}
+
+func ContractAddress(sender string, nonce uint64) (string, error) {
+	if !ValidateAddress(sender) {
+		return "", fmt.Errorf("invalid sender address")
+	}
+
+	senderBytes, err := normalizeHex(sender)
+	if err != nil {
+		return "", err
+	}
+
+	encoded, err := EncodeRLP([]interface{}{senderBytes, nonce})
+	if err != nil {
+		return "", err
+	}
+
+	return ToChecksumAddress("0x" + hex.EncodeToString(keccak256(encoded)[12:]))
+}
+
+func InitCodeHash(initCode []byte) [32]byte {
+	var hash [32]byte
+	copy(hash[:], keccak256(initCode))
+	return hash
+}
+
+func Create2Address(deployer string, salt [32]byte, initCodeHash [32]byte) (string, error) {
+	if !ValidateAddress(deployer) {
+		return "", fmt.Errorf("invalid deployer address")
+	}
+
+	deployerBytes, err := normalizeHex(deployer)
+	if err != nil {
+		return "", err
+	}
+
+	hash := keccak256([]byte{0xff}, deployerBytes, salt[:], initCodeHash[:])
+	return ToChecksumAddress("0x" + hex.EncodeToString(hash[12:]))
+}
@@ -0,0 +1,181 @@
+package web3
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeAddresses(t *testing.T) {
+	input := []string{
+		"0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed",
+		"0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359",
+		" 0xdbf03b407c01e7cd3cbea99509d93f8dddc8c6fb ",
+		"0x5AAEB6053F3E94C9B9A09F33669435E7EF1BEAED",
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD",
+		"0x1234",
+		"not an address",
+	}
+	want := []string{
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		"0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359",
+		"0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB",
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		"",
+		"",
+		"",
+	}
+	wantErr := []bool{false, false, false, false, true, true, true}
+
+	normalized, errs := NormalizeAddresses(input)
+	for i := range input {
+		if normalized[i] != want[i] {
+			t.Errorf("address %d = %q, want %q", i, normalized[i], want[i])
+		}
+		if (errs[i] != nil) != wantErr[i] {
+			t.Errorf("address %d error = %v, want error %v", i, errs[i], wantErr[i])
+		}
+	}
+}
+
+func TestToChecksumAddressChainID(t *testing.T) {
+	tests := []struct {
+		chainID int64
+		want    []string
+	}{
+		{30, []string{
+			"0x5aaEB6053f3e94c9b9a09f33669435E7ef1bEAeD",
+			"0xFb6916095cA1Df60bb79ce92cE3EA74c37c5d359",
+			"0xDBF03B407c01E7CD3cBea99509D93F8Dddc8C6FB",
+			"0xD1220A0Cf47c7B9BE7a2e6ba89F429762E7B9adB",
+		}},
+		{31, []string{
+			"0x5aAeb6053F3e94c9b9A09F33669435E7EF1BEaEd",
+			"0xFb6916095CA1dF60bb79CE92ce3Ea74C37c5D359",
+			"0xdbF03B407C01E7cd3cbEa99509D93f8dDDc8C6fB",
+			"0xd1220a0CF47c7B9Be7A2E6Ba89f429762E7b9adB",
+		}},
+	}
+
+	for _, tt := range tests {
+		for _, want := range tt.want {
+			got, err := ToChecksumAddressChainID(strings.ToLower(want), big.NewInt(tt.chainID))
+			if err != nil {
+				t.Fatalf("chain %d: %v", tt.chainID, err)
+			}
+			if got != want {
+				t.Errorf("chain %d: checksum = %s, want %s", tt.chainID, got, want)
+			}
+		}
+	}
+
+	if _, err := ToChecksumAddressChainID("0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", nil); err == nil {
+		t.Error("expected error for nil chain id")
+	}
+}
+
+func TestToChecksumAddressEIP55(t *testing.T) {
+	vectors := []string{
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		"0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359",
+		"0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB",
+		"0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb",
+	}
+
+	for _, want := range vectors {
+		got, err := ToChecksumAddress(strings.ToLower(want))
+		if err != nil {
+			t.Fatalf("ToChecksumAddress(%s) failed: %v", want, err)
+		}
+		if got != want {
+			t.Errorf("ToChecksumAddress = %s, want %s", got, want)
+		}
+		if !IsChecksumAddress(want) || !ValidateAddressChecksum(want) {
+			t.Errorf("%s should validate as checksummed", want)
+		}
+	}
+
+	if _, err := ToChecksumAddress("0x1234"); err == nil {
+		t.Error("expected error for short address")
+	}
+}
+
+func TestValidateAddressChecksum(t *testing.T) {
+	tests := []struct {
+		address    string
+		checksum   bool
+		acceptable bool
+	}{
+		{"0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", false, true},
+		{"0x5AAEB6053F3E94C9B9A09F33669435E7EF1BEAED", false, true},
+		{"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", true, true},
+		{"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD", false, false},
+		{"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeA", false, false},
+	}
+
+	for _, tt := range tests {
+		if got := IsChecksumAddress(tt.address); got != tt.checksum {
+			t.Errorf("IsChecksumAddress(%s) = %v, want %v", tt.address, got, tt.checksum)
+		}
+		if got := ValidateAddressChecksum(tt.address); got != tt.acceptable {
+			t.Errorf("ValidateAddressChecksum(%s) = %v, want %v", tt.address, got, tt.acceptable)
+		}
+	}
+}
+
+func TestContractAddress(t *testing.T) {
+	sender := "0x6ac7ea33f8831ea9dcc53393aaa88b25a785dbf0"
+	want := []string{
+		"0xcd234a471b72ba2f1ccf0a70fcaba648a5eecd8d",
+		"0x343c43a37d37dff08ae8c4a11544c718abb4fcf8",
+		"0xf778b86fa74e846c4f0a1fbd1335fe81c00a0c91",
+		"0xfffd933a0bc612844eaf0c6fe3e5b8e9b6c1d19c",
+	}
+	for nonce, expected := range want {
+		address, err := ContractAddress(sender, uint64(nonce))
+		if err != nil {
+			t.Fatalf("ContractAddress(nonce %d) failed: %v", nonce, err)
+		}
+		if !strings.EqualFold(address, expected) {
+			t.Errorf("nonce %d: address = %s, want %s", nonce, address, expected)
+		}
+		if !IsChecksumAddress(address) {
+			t.Errorf("nonce %d: %s is not checksummed", nonce, address)
+		}
+	}
+
+	if _, err := ContractAddress("0x6ac7ea33f8831ea9dcc53393aaa88b25a785db", 0); err == nil {
+		t.Error("expected error for a short sender address")
+	}
+}
+
+func TestCreate2AddressEIP1014Examples(t *testing.T) {
+	zero := "0x0000000000000000000000000000000000000000000000000000000000000000"
+	cafebabe := "0x00000000000000000000000000000000000000000000000000000000cafebabe"
+	tests := []struct {
+		deployer string
+		salt     string
+		initCode string
+		want     string
+	}{
+		{"0x0000000000000000000000000000000000000000", zero, "00", "0x4D1A2e2bB4F88F0250f26Ffff098B0b30B26BF38"},
+		{"0xdeadbeef00000000000000000000000000000000", zero, "00", "0xB928f69Bb1D91Cd65274e3c79d8986362984fDA3"},
+		{"0xdeadbeef00000000000000000000000000000000", "0x000000000000000000000000feed000000000000000000000000000000000000", "00", "0xD04116cDd17beBE565EB2422F2497E06cC1C9833"},
+		{"0x0000000000000000000000000000000000000000", zero, "deadbeef", "0x70f2b2914A2a4b783FaEFb75f459A580616Fcb5e"},
+		{"0x00000000000000000000000000000000deadbeef", cafebabe, "deadbeef", "0x60f3f640a8508fC6a86d45DF051962668E1e8AC7"},
+		{"0x00000000000000000000000000000000deadbeef", cafebabe, strings.Repeat("deadbeef", 11), "0x1d8bfDC5D46DC4f61D6b6115972536eBE6A8854C"},
+		{"0x0000000000000000000000000000000000000000", zero, "", "0xE33C0C7F7df4809055C3ebA6c09CFe4BaF1BD9e0"},
+	}
+
+	for i, tt := range tests {
+		var salt [32]byte
+		copy(salt[:], mustDecodeHex(t, tt.salt[2:]))
+		address, err := Create2Address(tt.deployer, salt, InitCodeHash(mustDecodeHex(t, tt.initCode)))
+		if err != nil {
+			t.Fatalf("example %d: Create2Address failed: %v", i, err)
+		}
+		if address != tt.want {
+			t.Errorf("example %d: address = %s, want %s", i, address, tt.want)
+		}
+	}
+}
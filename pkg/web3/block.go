@@ -0,0 +1,69 @@
+package web3
+
+import (
+	"fmt"
+	"math/big"
+)
+
+type Block struct {
+	Number        *big.Int
+	Hash          string
+	ParentHash    string
+	StateRoot     string
+	Timestamp     uint64
+	GasLimit      uint64
+	GasUsed       uint64
+	BaseFeePerGas *big.Int
+	Transactions  []string
+}
+
+type rpcBlock struct {
+	Number        string   `json:"number"`
+	Hash          string   `json:"hash"`
+	ParentHash    string   `json:"parentHash"`
+	StateRoot     string   `json:"stateRoot"`
+	Timestamp     string   `json:"timestamp"`
+	GasLimit      string   `json:"gasLimit"`
+	GasUsed       string   `json:"gasUsed"`
+	BaseFeePerGas *string  `json:"baseFeePerGas"`
+	Transactions  []string `json:"transactions"`
+}
+
+func (b *rpcBlock) toBlock() (*Block, error) {
+	number, err := decodeHexBig(b.Number)
+	if err != nil {
+		return nil, fmt.Errorf("invalid block number: %w", err)
+	}
+	timestamp, err := decodeHexUint64(b.Timestamp)
+	if err != nil {
+		return nil, fmt.Errorf("invalid block timestamp: %w", err)
+	}
+	gasLimit, err := decodeHexUint64(b.GasLimit)
+	if err != nil {
+		return nil, fmt.Errorf("invalid block gas limit: %w", err)
+	}
+	gasUsed, err := decodeHexUint64(b.GasUsed)
+	if err != nil {
+		return nil, fmt.Errorf("invalid block gas used: %w", err)
+	}
+
+	block := &Block{
+		Number:       number,
+		Hash:         b.Hash,
+		ParentHash:   b.ParentHash,
+		StateRoot:    b.StateRoot,
+		Timestamp:    timestamp,
+		GasLimit:     gasLimit,
+		GasUsed:      gasUsed,
+		Transactions: b.Transactions,
+	}
+
+	if b.BaseFeePerGas != nil {
+		block.BaseFeePerGas, err = decodeHexBig(*b.BaseFeePerGas)
+		if err != nil {
+			return nil, fmt.Errorf("invalid block base fee: %w", err)
+		}
+	}
+
+	return block, nil
+}
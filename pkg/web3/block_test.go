@@ -0,0 +1,100 @@
+package web3
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func stubBlock(number string, baseFee interface{}) map[string]interface{} {
+	block := map[string]interface{}{
+		"number":       number,
+		"hash":         "0x" + strings.Repeat("ab", 32),
+		"parentHash":   "0x" + strings.Repeat("cd", 32),
+		"stateRoot":    "0x" + strings.Repeat("ef", 32),
+		"timestamp":    "0x6553f100",
+		"gasLimit":     "0x1c9c380",
+		"gasUsed":      "0xe4e1c0",
+		"transactions": []string{},
+	}
+	if baseFee != nil {
+		block["baseFeePerGas"] = baseFee
+	}
+	return block
+}
+
+func TestBaseFee(t *testing.T) {
+	client, _ := newFakeClient(func(method string, params []json.RawMessage) (interface{}, *RPCError) {
+		if method != "eth_getBlockByNumber" || string(params[0]) != `"latest"` {
+			t.Fatalf("unexpected call %s %s", method, params)
+		}
+		return stubBlock("0x10", "0x3b9aca00"), nil
+	})
+
+	baseFee, err := client.BaseFee(context.Background())
+	if err != nil {
+		t.Fatalf("BaseFee failed: %v", err)
+	}
+	if baseFee.Int64() != 1_000_000_000 {
+		t.Errorf("base fee = %s, want 1000000000", baseFee)
+	}
+}
+
+func TestBaseFeePreLondon(t *testing.T) {
+	client, _ := newFakeClient(func(method string, params []json.RawMessage) (interface{}, *RPCError) {
+		return stubBlock("0x10", nil), nil
+	})
+
+	_, err := client.BaseFee(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "no base fee") {
+		t.Fatalf("error = %v, want missing base fee", err)
+	}
+
+	block, err := client.GetBlockByNumber(context.Background(), "")
+	if err != nil {
+		t.Fatalf("GetBlockByNumber failed: %v", err)
+	}
+	if block.BaseFeePerGas != nil || block.Number.Int64() != 16 {
+		t.Errorf("block = %+v, want number 16 without base fee", block)
+	}
+}
+
+func TestIsTxInBlock(t *testing.T) {
+	included := "0x" + strings.Repeat("11", 32)
+	blockHash := "0x" + strings.Repeat("ab", 32)
+	client, _ := newFakeClient(func(method string, params []json.RawMessage) (interface{}, *RPCError) {
+		if method != "eth_getBlockByHash" || string(params[0]) != `"`+blockHash+`"` {
+			t.Fatalf("unexpected call %s %s", method, params)
+		}
+		block := stubBlock("0x10", "0x7")
+		block["transactions"] = []string{"0x" + strings.Repeat("22", 32), included}
+		return block, nil
+	})
+
+	found, err := client.IsTxInBlock(context.Background(), "0x"+strings.Repeat("11", 32), blockHash)
+	if err != nil {
+		t.Fatalf("IsTxInBlock failed: %v", err)
+	}
+	if !found {
+		t.Error("expected included transaction to be found")
+	}
+
+	found, err = client.IsTxInBlock(context.Background(), "0x"+strings.Repeat("33", 32), blockHash)
+	if err != nil {
+		t.Fatalf("IsTxInBlock failed: %v", err)
+	}
+	if found {
+		t.Error("transaction absent from the block was reported as included")
+	}
+}
+
+func TestIsTxInBlockMissingBlock(t *testing.T) {
+	client, _ := newFakeClient(func(method string, params []json.RawMessage) (interface{}, *RPCError) {
+		return nil, nil
+	})
+
+	if _, err := client.IsTxInBlock(context.Background(), "0x"+strings.Repeat("11", 32), "0x"+strings.Repeat("ab", 32)); err == nil {
+		t.Fatal("expected error for unknown block")
+	}
+}
@@ -0,0 +1,211 @@
+package web3
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	blockHistorySize         = 128
+	DefaultBlockPollInterval = 12 * time.Second
+)
+
+type ReorgEvent struct {
+	Depth          uint64
+	CommonAncestor uint64
+}
+
+type BlockMonitor struct {
+	client   *Client
+	interval time.Duration
+	blocks   chan *Block
+	reorgs   chan ReorgEvent
+	errors   chan error
+
+	// pollMu serializes Poll and guards the tracking state below. It is kept
+	// separate from mu so Stop can cancel a poll blocked on a full channel.
+	pollMu     sync.Mutex
+	started    bool
+	lastNumber uint64
+	history    map[uint64]string
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+}
+
+// NewBlockMonitor polls every interval once started, falling back to
+// DefaultBlockPollInterval when interval is not positive.
+func NewBlockMonitor(client *Client, interval time.Duration) *BlockMonitor {
+	if interval <= 0 {
+		interval = DefaultBlockPollInterval
+	}
+	return &BlockMonitor{
+		client:   client,
+		interval: interval,
+		blocks:   make(chan *Block, 100),
+		reorgs:   make(chan ReorgEvent, 100),
+		errors:   make(chan error, 100),
+		history:  make(map[uint64]string),
+	}
+}
+
+func (bm *BlockMonitor) Blocks() <-chan *Block {
+	return bm.blocks
+}
+
+func (bm *BlockMonitor) Reorgs() <-chan ReorgEvent {
+	return bm.reorgs
+}
+
+func (bm *BlockMonitor) Errors() <-chan error {
+	return bm.errors
+}
+
+func (bm *BlockMonitor) Start(ctx context.Context) {
+	bm.mu.Lock()
+	defer bm.mu.Unlock()
+	if bm.cancel != nil {
+		return
+	}
+
+	ctx, bm.cancel = context.WithCancel(ctx)
+	go func() {
+		ticker := time.NewTicker(bm.interval)
+		defer ticker.Stop()
+
+		for {
+			if err := bm.Poll(ctx); err != nil && ctx.Err() == nil {
+				select {
+				case bm.errors <- err:
+				default:
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (bm *BlockMonitor) Stop() {
+	bm.mu.Lock()
+	defer bm.mu.Unlock()
+	if bm.cancel != nil {
+		bm.cancel()
+		bm.cancel = nil
+	}
+}
+
+// Poll is safe to call while the monitor is running; polls never overlap.
+func (bm *BlockMonitor) Poll(ctx context.Context) error {
+	bm.pollMu.Lock()
+	defer bm.pollMu.Unlock()
+
+	headNumber, err := bm.client.blockNumber(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch block number: %w", err)
+	}
+	head := headNumber.Uint64()
+
+	if !bm.started {
+		block, err := bm.fetchBlock(ctx, head)
+		if err != nil {
+			return err
+		}
+		bm.started = true
+		return bm.deliver(ctx, block)
+	}
+
+	for next := bm.lastNumber + 1; next <= head; next++ {
+		block, err := bm.fetchBlock(ctx, next)
+		if err != nil {
+			return err
+		}
+
+		parentHash, known := bm.history[next-1]
+		if known && !strings.EqualFold(block.ParentHash, parentHash) {
+			if err := bm.rewind(ctx, next-1); err != nil {
+				return err
+			}
+			next = bm.lastNumber
+			continue
+		}
+
+		if err := bm.deliver(ctx, block); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (bm *BlockMonitor) rewind(ctx context.Context, from uint64) error {
+	ancestor := from
+	for {
+		recorded, known := bm.history[ancestor]
+		if !known {
+			return fmt.Errorf("reorg at block %d is deeper than the %d block history", from, blockHistorySize)
+		}
+
+		canonical, err := bm.fetchBlock(ctx, ancestor)
+		if err != nil {
+			return err
+		}
+		if strings.EqualFold(canonical.Hash, recorded) {
+			break
+		}
+
+		delete(bm.history, ancestor)
+		if ancestor == 0 {
+			return fmt.Errorf("reorg reached genesis without a common ancestor")
+		}
+		ancestor--
+	}
+
+	bm.lastNumber = ancestor
+	event := ReorgEvent{Depth: from - ancestor, CommonAncestor: ancestor}
+	select {
+	case bm.reorgs <- event:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (bm *BlockMonitor) fetchBlock(ctx context.Context, number uint64) (*Block, error) {
+	result, err := bm.client.CallContext(ctx, "eth_getBlockByNumber", encodeHexUint64(number), false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch block %d: %w", number, err)
+	}
+
+	block, err := decodeBlockResult(result)
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, fmt.Errorf("block %d not found", number)
+	}
+	return block, nil
+}
+
+func (bm *BlockMonitor) deliver(ctx context.Context, block *Block) error {
+	number := block.Number.Uint64()
+	bm.lastNumber = number
+	bm.history[number] = block.Hash
+	if number >= blockHistorySize {
+		delete(bm.history, number-blockHistorySize)
+	}
+
+	select {
+	case bm.blocks <- block:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
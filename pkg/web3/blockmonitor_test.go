@@ -0,0 +1,178 @@
+package web3
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// chainStub serves a linear chain whose head can be advanced between polls.
+// After fork is called, blocks from the fork point on are replaced by blocks
+// with different hashes, as a node does after a reorg.
+type chainStub struct {
+	mu       sync.Mutex
+	head     uint64
+	forkFrom uint64
+}
+
+func (c *chainStub) setHead(head uint64) {
+	c.mu.Lock()
+	c.head = head
+	c.mu.Unlock()
+}
+
+func (c *chainStub) fork(from uint64) {
+	c.mu.Lock()
+	c.forkFrom = from
+	c.mu.Unlock()
+}
+
+func (c *chainStub) hash(number uint64) string {
+	var variant uint64
+	if c.forkFrom > 0 && number >= c.forkFrom {
+		variant = 1
+	}
+	return fmt.Sprintf("0x%032x%032x", variant, number+1)
+}
+
+func (c *chainStub) handle(method string, params []json.RawMessage) (interface{}, *RPCError) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch method {
+	case "eth_blockNumber":
+		return encodeHexUint64(c.head), nil
+	case "eth_getBlockByNumber":
+		var tag string
+		json.Unmarshal(params[0], &tag)
+		number, err := decodeHexUint64(tag)
+		if err != nil || number > c.head {
+			return nil, nil
+		}
+		block := stubBlock(tag, "0x7")
+		block["hash"] = c.hash(number)
+		block["parentHash"] = fmt.Sprintf("0x%064x", 0)
+		if number > 0 {
+			block["parentHash"] = c.hash(number - 1)
+		}
+		return block, nil
+	default:
+		return nil, &RPCError{Code: -32601, Message: "method not found"}
+	}
+}
+
+func TestBlockMonitorFillsGaps(t *testing.T) {
+	chain := &chainStub{head: 100}
+	client, _ := newFakeClient(chain.handle)
+	monitor := NewBlockMonitor(client, 0)
+	ctx := context.Background()
+
+	for _, head := range []uint64{100, 101, 103} {
+		chain.setHead(head)
+		if err := monitor.Poll(ctx); err != nil {
+			t.Fatalf("Poll at head %d failed: %v", head, err)
+		}
+	}
+
+	var numbers []uint64
+	for len(monitor.Blocks()) > 0 {
+		numbers = append(numbers, (<-monitor.Blocks()).Number.Uint64())
+	}
+	want := []uint64{100, 101, 102, 103}
+	if fmt.Sprint(numbers) != fmt.Sprint(want) {
+		t.Fatalf("delivered blocks %v, want %v", numbers, want)
+	}
+	if len(monitor.Reorgs()) != 0 {
+		t.Error("linear chain reported a reorg")
+	}
+}
+
+func TestBlockMonitorReorg(t *testing.T) {
+	chain := &chainStub{head: 100}
+	client, _ := newFakeClient(chain.handle)
+	monitor := NewBlockMonitor(client, 0)
+	ctx := context.Background()
+
+	for _, head := range []uint64{100, 103} {
+		chain.setHead(head)
+		if err := monitor.Poll(ctx); err != nil {
+			t.Fatalf("Poll at head %d failed: %v", head, err)
+		}
+	}
+	for len(monitor.Blocks()) > 0 {
+		<-monitor.Blocks()
+	}
+
+	// Block 102 is replaced, so 103 and the new head build on the new branch.
+	chain.fork(102)
+	chain.setHead(104)
+	if err := monitor.Poll(ctx); err != nil {
+		t.Fatalf("Poll after reorg failed: %v", err)
+	}
+
+	select {
+	case event := <-monitor.Reorgs():
+		if event.CommonAncestor != 101 || event.Depth != 2 {
+			t.Errorf("reorg = %+v, want depth 2 from ancestor 101", event)
+		}
+	default:
+		t.Fatal("expected a reorg event")
+	}
+
+	var delivered []string
+	for len(monitor.Blocks()) > 0 {
+		block := <-monitor.Blocks()
+		delivered = append(delivered, fmt.Sprintf("%d:%s", block.Number.Uint64(), block.Hash))
+	}
+	var want []string
+	for number := uint64(102); number <= 104; number++ {
+		want = append(want, fmt.Sprintf("%d:%s", number, chain.hash(number)))
+	}
+	if fmt.Sprint(delivered) != fmt.Sprint(want) {
+		t.Fatalf("delivered blocks %v, want the replacement blocks %v", delivered, want)
+	}
+}
+
+func TestNewBlockMonitorDefaultsInterval(t *testing.T) {
+	for _, interval := range []time.Duration{0, -time.Second} {
+		if monitor := NewBlockMonitor(nil, interval); monitor.interval != DefaultBlockPollInterval {
+			t.Errorf("interval %s became %s, want %s", interval, monitor.interval, DefaultBlockPollInterval)
+		}
+	}
+}
+
+func TestBlockMonitorConcurrentPolls(t *testing.T) {
+	chain := &chainStub{head: 10}
+	client, _ := newFakeClient(chain.handle)
+	monitor := NewBlockMonitor(client, 0)
+	if err := monitor.Poll(context.Background()); err != nil {
+		t.Fatalf("initial Poll failed: %v", err)
+	}
+	<-monitor.Blocks()
+
+	chain.setHead(20)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			monitor.Poll(context.Background())
+		}()
+	}
+	wg.Wait()
+
+	seen := make(map[uint64]bool)
+	for len(monitor.Blocks()) > 0 {
+		number := (<-monitor.Blocks()).Number.Uint64()
+		if seen[number] {
+			t.Fatalf("block %d delivered twice", number)
+		}
+		seen[number] = true
+	}
+	if len(seen) != 10 {
+		t.Errorf("delivered %d blocks, want 10", len(seen))
+	}
+}
@@ -0,0 +1,55 @@
+package web3
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+type CallMsg struct {
+	From string
+	To   string
+	Data []byte
+}
+
+func NewCall(to string, data []byte) *CallMsg {
+	return &CallMsg{
+		To:   to,
+		Data: data,
+	}
+}
+
+func (msg *CallMsg) WithFrom(from string) *CallMsg {
+	msg.From = from
+	return msg
+}
+
+// MarshalJSON encodes the eth_call argument object, so a CallMsg can be passed
+// straight to Client.Call or Client.CallContext.
+func (msg *CallMsg) MarshalJSON() ([]byte, error) {
+	args, err := msg.callArgs()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(args)
+}
+
+func (msg *CallMsg) callArgs() (map[string]interface{}, error) {
+	if !ValidateAddress(msg.To) {
+		return nil, fmt.Errorf("invalid call target address")
+	}
+
+	args := map[string]interface{}{
+		"to": msg.To,
+	}
+	if msg.From != "" {
+		if !ValidateAddress(msg.From) {
+			return nil, fmt.Errorf("invalid call sender address")
+		}
+		args["from"] = msg.From
+	}
+	if len(msg.Data) > 0 {
+		args["data"] = "0x" + hex.EncodeToString(msg.Data)
+	}
+	return args, nil
+}
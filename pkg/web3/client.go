@@ -0,0 +1,690 @@
+package web3
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	ErrTransactionReverted = errors.New("transaction reverted")
+	ErrReceiptTimeout      = errors.New("timed out waiting for receipt")
+	ErrTooManyLogs         = errors.New("log query exceeds node result limit, narrow the block range")
+)
+
+type Client struct {
+	transport Transport
+	requestID uint64
+}
+
+type RPCError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("rpc error %d: %s", e.Code, e.Message)
+}
+
+type rpcRequest struct {
+	JSONRPC string        `json:"jsonrpc"`
+	ID      uint64        `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result"`
+	Error   *RPCError       `json:"error"`
+}
+
+func NewClient(rpcURL string) *Client {
+	return NewClientWithTransport(NewHTTPTransport(rpcURL))
+}
+
+func NewClientWithTransport(transport Transport) *Client {
+	return &Client{transport: transport}
+}
+
+func (c *Client) CallContext(ctx context.Context, method string, params ...interface{}) (json.RawMessage, error) {
+	if params == nil {
+		params = []interface{}{}
+	}
+
+	payload, err := json.Marshal(rpcRequest{
+		JSONRPC: "2.0",
+		ID:      atomic.AddUint64(&c.requestID, 1),
+		Method:  method,
+		Params:  params,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	body, err := c.transport.Do(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp rpcResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("invalid rpc response: %w", err)
+	}
+
+	if resp.Error != nil {
+		return nil, resp.Error
+	}
+
+	return resp.Result, nil
+}
+
+func (c *Client) Call(method string, params ...interface{}) (json.RawMessage, error) {
+	return c.CallContext(context.Background(), method, params...)
+}
+
+func (c *Client) BlockNumber() (*big.Int, error) {
+	return c.blockNumber(context.Background())
+}
+
+func (c *Client) blockNumber(ctx context.Context) (*big.Int, error) {
+	result, err := c.CallContext(ctx, "eth_blockNumber")
+	if err != nil {
+		return nil, err
+	}
+	return decodeQuantityResult(result)
+}
+
+func (c *Client) GetBalance(address string, block string) (*big.Int, error) {
+	if !ValidateAddress(address) {
+		return nil, fmt.Errorf("invalid account address")
+	}
+	if block == "" {
+		block = "latest"
+	}
+
+	result, err := c.Call("eth_getBalance", address, block)
+	if err != nil {
+		return nil, err
+	}
+	return decodeQuantityResult(result)
+}
+
+func (c *Client) PendingNonceAt(address string) (uint64, error) {
+	return c.NonceAt(address, "pending")
+}
+
+func (c *Client) NonceAt(address, block string) (uint64, error) {
+	if !ValidateAddress(address) {
+		return 0, fmt.Errorf("invalid account address")
+	}
+	if block == "" {
+		block = "latest"
+	}
+
+	result, err := c.Call("eth_getTransactionCount", address, block)
+	if err != nil {
+		return 0, err
+	}
+
+	nonce, err := decodeQuantityResult(result)
+	if err != nil {
+		return 0, fmt.Errorf("invalid nonce: %w", err)
+	}
+	if !nonce.IsUint64() {
+		return 0, fmt.Errorf("nonce overflows uint64")
+	}
+	return nonce.Uint64(), nil
+}
+
+func (c *Client) SendRawTransaction(raw string) (string, error) {
+	rawBytes, err := normalizeHex(raw)
+	if err != nil || len(rawBytes) == 0 {
+		return "", fmt.Errorf("invalid raw transaction hex")
+	}
+
+	result, err := c.Call("eth_sendRawTransaction", "0x"+hex.EncodeToString(rawBytes))
+	if err != nil {
+		return "", err
+	}
+
+	var txHash string
+	if err := json.Unmarshal(result, &txHash); err != nil {
+		return "", fmt.Errorf("invalid transaction hash response: %w", err)
+	}
+	return txHash, nil
+}
+
+func (c *Client) EstimateGas(tx *Transaction, from string) (uint64, error) {
+	if tx == nil {
+		return 0, fmt.Errorf("transaction is required")
+	}
+
+	args, err := transactionCallArgs(tx)
+	if err != nil {
+		return 0, err
+	}
+	if from != "" {
+		if !ValidateAddress(from) {
+			return 0, fmt.Errorf("invalid sender address")
+		}
+		args["from"] = from
+	}
+
+	result, err := c.Call("eth_estimateGas", args)
+	if err != nil {
+		return 0, err
+	}
+
+	var value string
+	if err := json.Unmarshal(result, &value); err != nil {
+		return 0, fmt.Errorf("invalid gas estimate response: %w", err)
+	}
+	return decodeHexUint64(value)
+}
+
+func (c *Client) SuggestGasPrice() (*big.Int, error) {
+	result, err := c.Call("eth_gasPrice")
+	if err != nil {
+		return nil, err
+	}
+	return decodeQuantityResult(result)
+}
+
+func (c *Client) SuggestFeeData() (*FeeData, error) {
+	result, err := c.Call("eth_feeHistory", encodeHexUint64(1), "latest", []float64{})
+	if err != nil {
+		return nil, err
+	}
+
+	var history struct {
+		BaseFeePerGas []string `json:"baseFeePerGas"`
+	}
+	if err := json.Unmarshal(result, &history); err != nil {
+		return nil, fmt.Errorf("invalid fee history response: %w", err)
+	}
+	if len(history.BaseFeePerGas) == 0 {
+		return nil, fmt.Errorf("fee history has no base fee, chain is not London-enabled")
+	}
+
+	baseFee, err := decodeHexBig(history.BaseFeePerGas[len(history.BaseFeePerGas)-1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid base fee: %w", err)
+	}
+
+	result, err = c.Call("eth_maxPriorityFeePerGas")
+	if err != nil {
+		return nil, err
+	}
+	maxPriorityFee, err := decodeQuantityResult(result)
+	if err != nil {
+		return nil, fmt.Errorf("invalid max priority fee: %w", err)
+	}
+
+	return newFeeData(baseFee, maxPriorityFee), nil
+}
+
+func (c *Client) GetTransactionReceipt(hash string) (*TransactionReceipt, error) {
+	return c.getTransactionReceipt(context.Background(), hash)
+}
+
+func (c *Client) WaitForReceipt(ctx context.Context, hash string, pollInterval time.Duration) (*TransactionReceipt, error) {
+	if pollInterval <= 0 {
+		return nil, fmt.Errorf("poll interval must be positive")
+	}
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		receipt, err := c.getTransactionReceipt(ctx, hash)
+		if err != nil && ctx.Err() == nil {
+			return nil, err
+		}
+		if receipt != nil {
+			if receipt.HasStatus && receipt.Status == 0 {
+				return receipt, fmt.Errorf("%w: %s", ErrTransactionReverted, hash)
+			}
+			return receipt, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			// Only a passed deadline is a timeout; a cancelled context means
+			// the caller gave up and should see context.Canceled.
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return nil, fmt.Errorf("%w: %s", ErrReceiptTimeout, hash)
+			}
+			return nil, fmt.Errorf("stopped waiting for receipt %s: %w", hash, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *Client) getTransactionReceipt(ctx context.Context, hash string) (*TransactionReceipt, error) {
+	result, err := c.CallContext(ctx, "eth_getTransactionReceipt", hash)
+	if err != nil {
+		return nil, err
+	}
+	if string(result) == "null" {
+		return nil, nil
+	}
+
+	var raw rpcReceipt
+	if err := json.Unmarshal(result, &raw); err != nil {
+		return nil, fmt.Errorf("invalid receipt response: %w", err)
+	}
+
+	return raw.toReceipt()
+}
+
+func (c *Client) GetLogs(filter *EventFilter) ([]Event, error) {
+	if filter != nil {
+		if err := filter.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	params := logFilterParams(filter)
+	if filter != nil {
+		if filter.FromBlock != nil {
+			tag, err := filterBlockTag(filter.FromBlock)
+			if err != nil {
+				return nil, err
+			}
+			params["fromBlock"] = tag
+		}
+		if filter.ToBlock != nil {
+			tag, err := filterBlockTag(filter.ToBlock)
+			if err != nil {
+				return nil, err
+			}
+			params["toBlock"] = tag
+		}
+	}
+
+	result, err := c.Call("eth_getLogs", params)
+	if err != nil {
+		if isTooManyLogsError(err) {
+			return nil, fmt.Errorf("%w: %w", ErrTooManyLogs, err)
+		}
+		return nil, err
+	}
+
+	var raw []rpcLog
+	if err := json.Unmarshal(result, &raw); err != nil {
+		return nil, fmt.Errorf("invalid logs response: %w", err)
+	}
+
+	events := make([]Event, 0, len(raw))
+	for i := range raw {
+		event, err := raw[i].toEvent()
+		if err != nil {
+			return nil, fmt.Errorf("invalid log %d: %w", i, err)
+		}
+		events = append(events, *event)
+	}
+	return events, nil
+}
+
+func filterBlockTag(block *big.Int) (string, error) {
+	switch {
+	case block.Cmp(big.NewInt(-1)) == 0:
+		return "latest", nil
+	case block.Cmp(big.NewInt(-2)) == 0:
+		return "pending", nil
+	case block.Sign() < 0:
+		return "", fmt.Errorf("invalid filter block %s", block.String())
+	}
+	return encodeHexBig(block)
+}
+
+// Nodes word this differently: geth and Infura say "query returned more than
+// 10000 results", Alchemy says "log response size exceeded", others report
+// -32005. A bare "more than" or "limit exceeded" also shows up in unrelated
+// errors such as rate limiting, so only the full phrases are matched.
+var tooManyLogsMessages = []string{
+	"query returned more than",
+	"log response size exceeded",
+	"too many logs",
+	"too many results",
+}
+
+func isTooManyLogsError(err error) bool {
+	var rpcErr *RPCError
+	if !errors.As(err, &rpcErr) {
+		return false
+	}
+	if rpcErr.Code == -32005 {
+		return true
+	}
+
+	message := strings.ToLower(rpcErr.Message)
+	for _, phrase := range tooManyLogsMessages {
+		if strings.Contains(message, phrase) {
+			return true
+		}
+	}
+	return false
+}
+
+func decodeQuantityResult(result json.RawMessage) (*big.Int, error) {
+	var value string
+	if err := json.Unmarshal(result, &value); err != nil {
+		return nil, fmt.Errorf("invalid quantity response: %w", err)
+	}
+	return decodeHexBig(value)
+}
+
+func (c *Client) GetStorageAt(ctx context.Context, address string, slot *big.Int, block string) ([]byte, error) {
+	if !ValidateAddress(address) {
+		return nil, fmt.Errorf("invalid contract address")
+	}
+	if block == "" {
+		block = "latest"
+	}
+
+	result, err := c.CallContext(ctx, "eth_getStorageAt", address, fmt.Sprintf("0x%064x", slot), block)
+	if err != nil {
+		return nil, err
+	}
+
+	var value string
+	if err := json.Unmarshal(result, &value); err != nil {
+		return nil, fmt.Errorf("invalid storage value: %w", err)
+	}
+
+	data, err := normalizeHex(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid storage value: %w", err)
+	}
+
+	return data, nil
+}
+
+func (c *Client) callContract(ctx context.Context, msg *CallMsg, block string) ([]byte, error) {
+	if block == "" {
+		block = "latest"
+	}
+
+	result, err := c.CallContext(ctx, "eth_call", msg, block)
+	if err != nil {
+		return nil, err
+	}
+
+	var value string
+	if err := json.Unmarshal(result, &value); err != nil {
+		return nil, fmt.Errorf("invalid call result: %w", err)
+	}
+
+	data, err := normalizeHex(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid call result: %w", err)
+	}
+
+	return data, nil
+}
+
+func (c *Client) CallContract(to string, data []byte, block string) ([]byte, error) {
+	result, err := c.callContract(context.Background(), NewCall(to, data), block)
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("call to %s returned no data, the contract may not exist or reverted", to)
+	}
+	return result, nil
+}
+
+func (c *Client) ReadBalanceOf(token, owner string) (*big.Int, error) {
+	data, err := NewERC20Token(token, "", "", 0).EncodeBalanceOf(owner)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := c.CallContract(token, data, "latest")
+	if err != nil {
+		return nil, err
+	}
+	if len(result) < 32 {
+		return nil, fmt.Errorf("insufficient data for balance")
+	}
+	return new(big.Int).SetBytes(result[:32]), nil
+}
+
+func (c *Client) CheckAllowance(ctx context.Context, token, owner, spender string, needed *big.Int) (bool, *big.Int, error) {
+	if needed == nil || needed.Sign() < 0 {
+		return false, nil, fmt.Errorf("needed amount must be non-negative")
+	}
+
+	data, err := NewERC20Token(token, "", "", 0).EncodeAllowance(owner, spender)
+	if err != nil {
+		return false, nil, err
+	}
+
+	result, err := c.callContract(ctx, NewCall(token, data), "latest")
+	if err != nil {
+		return false, nil, err
+	}
+	if len(result) < 32 {
+		return false, nil, fmt.Errorf("insufficient data for allowance")
+	}
+
+	allowance := new(big.Int).SetBytes(result[:32])
+	return allowance.Cmp(needed) >= 0, allowance, nil
+}
+
+func (c *Client) CreateAccessList(ctx context.Context, tx *Transaction) (AccessList, uint64, error) {
+	if tx == nil {
+		return nil, 0, fmt.Errorf("transaction is required")
+	}
+
+	args, err := transactionCallArgs(tx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	result, err := c.CallContext(ctx, "eth_createAccessList", args, "latest")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var response struct {
+		AccessList AccessList `json:"accessList"`
+		GasUsed    string     `json:"gasUsed"`
+		Error      string     `json:"error"`
+	}
+	if err := json.Unmarshal(result, &response); err != nil {
+		return nil, 0, fmt.Errorf("invalid access list response: %w", err)
+	}
+	if response.Error != "" {
+		return nil, 0, fmt.Errorf("access list creation failed: %s", response.Error)
+	}
+
+	gasUsed, err := decodeHexUint64(response.GasUsed)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid gas used: %w", err)
+	}
+
+	return response.AccessList, gasUsed, nil
+}
+
+func transactionCallArgs(tx *Transaction) (map[string]interface{}, error) {
+	args := make(map[string]interface{})
+	if tx.From != "" {
+		args["from"] = tx.From
+	}
+	if tx.To != "" {
+		args["to"] = tx.To
+	}
+	if tx.Gas > 0 {
+		args["gas"] = encodeHexUint64(tx.Gas)
+	}
+
+	// Nodes reject calls that mix gasPrice with the EIP-1559 fee fields, so
+	// only the pricing fields of the transaction's own type are sent.
+	quantities := map[string]*big.Int{"value": tx.Value}
+	if tx.Type == DynamicFeeTxType {
+		quantities["maxFeePerGas"] = tx.MaxFeePerGas
+		quantities["maxPriorityFeePerGas"] = tx.MaxPriorityFeePerGas
+	} else {
+		quantities["gasPrice"] = tx.GasPrice
+	}
+	for name, value := range quantities {
+		if value == nil {
+			continue
+		}
+		encoded, err := encodeHexBig(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", name, err)
+		}
+		args[name] = encoded
+	}
+
+	if len(tx.AccessList) > 0 {
+		args["accessList"] = tx.AccessList
+	}
+	if len(tx.Data) > 0 {
+		args["data"] = "0x" + hex.EncodeToString(tx.Data)
+	}
+	return args, nil
+}
+
+func encodeHexBig(value *big.Int) (string, error) {
+	if value.Sign() < 0 {
+		return "", fmt.Errorf("negative quantity %s cannot be hex encoded", value.String())
+	}
+	return "0x" + value.Text(16), nil
+}
+
+func encodeHexUint64(value uint64) string {
+	return fmt.Sprintf("0x%x", value)
+}
+
+func decodeHexBig(value string) (*big.Int, error) {
+	if !strings.HasPrefix(value, "0x") || len(value) == 2 {
+		return nil, fmt.Errorf("invalid hex quantity: %q", value)
+	}
+
+	result, ok := new(big.Int).SetString(value[2:], 16)
+	if !ok {
+		return nil, fmt.Errorf("invalid hex quantity: %q", value)
+	}
+	return result, nil
+}
+
+func decodeHexUint64(value string) (uint64, error) {
+	result, err := decodeHexBig(value)
+	if err != nil {
+		return 0, err
+	}
+	if !result.IsUint64() {
+		return 0, fmt.Errorf("hex quantity overflows uint64: %q", value)
+	}
+	return result.Uint64(), nil
+}
+
+func (c *Client) GetBlockByNumber(ctx context.Context, block string) (*Block, error) {
+	if block == "" {
+		block = "latest"
+	}
+
+	result, err := c.CallContext(ctx, "eth_getBlockByNumber", block, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeBlockResult(result)
+}
+
+func (c *Client) BaseFee(ctx context.Context) (*big.Int, error) {
+	block, err := c.GetBlockByNumber(ctx, "latest")
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, fmt.Errorf("latest block not found")
+	}
+	if block.BaseFeePerGas == nil {
+		return nil, fmt.Errorf("block %s has no base fee, chain is not London-enabled", block.Number.String())
+	}
+
+	return block.BaseFeePerGas, nil
+}
+
+func decodeBlockResult(result json.RawMessage) (*Block, error) {
+	if string(result) == "null" {
+		return nil, nil
+	}
+
+	var raw rpcBlock
+	if err := json.Unmarshal(result, &raw); err != nil {
+		return nil, fmt.Errorf("invalid block response: %w", err)
+	}
+
+	return raw.toBlock()
+}
+
+func (c *Client) GetProof(ctx context.Context, addr string, slots []*big.Int, blockTag string) (*AccountProof, error) {
+	if !ValidateAddress(addr) {
+		return nil, fmt.Errorf("invalid account address")
+	}
+	if blockTag == "" {
+		blockTag = "latest"
+	}
+
+	keys := make([]string, len(slots))
+	for i, slot := range slots {
+		if slot == nil || slot.Sign() < 0 {
+			return nil, fmt.Errorf("invalid storage slot at index %d", i)
+		}
+		keys[i] = fmt.Sprintf("0x%064x", slot)
+	}
+
+	result, err := c.CallContext(ctx, "eth_getProof", addr, keys, blockTag)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw rpcAccountProof
+	if err := json.Unmarshal(result, &raw); err != nil {
+		return nil, fmt.Errorf("invalid proof response: %w", err)
+	}
+
+	return raw.toAccountProof()
+}
+
+func (c *Client) GetBlockByHash(ctx context.Context, blockHash string) (*Block, error) {
+	result, err := c.CallContext(ctx, "eth_getBlockByHash", blockHash, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeBlockResult(result)
+}
+
+func (c *Client) IsTxInBlock(ctx context.Context, txHash string, blockHash string) (bool, error) {
+	block, err := c.GetBlockByHash(ctx, blockHash)
+	if err != nil {
+		return false, err
+	}
+	if block == nil {
+		return false, fmt.Errorf("block %s not found", blockHash)
+	}
+
+	for _, hash := range block.Transactions {
+		if strings.EqualFold(hash, txHash) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
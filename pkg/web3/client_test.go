@@ -0,0 +1,441 @@
+package web3
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCreateAccessList(t *testing.T) {
+	key := "0x0000000000000000000000000000000000000000000000000000000000000003"
+	client, _ := newFakeClient(func(method string, params []json.RawMessage) (interface{}, *RPCError) {
+		if method != "eth_createAccessList" {
+			t.Fatalf("unexpected method %s", method)
+		}
+
+		var args map[string]interface{}
+		json.Unmarshal(params[0], &args)
+		if _, ok := args["gasPrice"]; ok {
+			t.Errorf("dynamic fee call args include gasPrice: %v", args)
+		}
+		if args["maxFeePerGas"] != "0x77359400" {
+			t.Errorf("maxFeePerGas = %v, want 0x77359400", args["maxFeePerGas"])
+		}
+
+		return map[string]interface{}{
+			"accessList": []map[string]interface{}{
+				{"address": testTokenAddress, "storageKeys": []string{key}},
+			},
+			"gasUsed": "0x5a3c",
+		}, nil
+	})
+
+	list, gasUsed, err := client.CreateAccessList(context.Background(), &Transaction{
+		Type:         DynamicFeeTxType,
+		To:           testTokenAddress,
+		GasPrice:     big.NewInt(1),
+		MaxFeePerGas: big.NewInt(2_000_000_000),
+		Data:         []byte{0x70, 0xa0, 0x82, 0x31},
+	})
+	if err != nil {
+		t.Fatalf("CreateAccessList failed: %v", err)
+	}
+	if gasUsed != 0x5a3c {
+		t.Errorf("gasUsed = %d, want %d", gasUsed, 0x5a3c)
+	}
+	if len(list) != 1 || list[0].Address != testTokenAddress || len(list[0].StorageKeys) != 1 || list[0].StorageKeys[0] != key {
+		t.Errorf("access list = %+v", list)
+	}
+}
+
+func TestTransactionCallArgsRejectsNegativeValue(t *testing.T) {
+	client, _ := newFakeClient(func(string, []json.RawMessage) (interface{}, *RPCError) {
+		t.Fatal("request sent for a negative value")
+		return nil, nil
+	})
+
+	_, _, err := client.CreateAccessList(context.Background(), &Transaction{To: testTokenAddress, Value: big.NewInt(-1)})
+	if err == nil {
+		t.Fatal("expected error for negative value")
+	}
+}
+
+func TestCallWithCallMsg(t *testing.T) {
+	balance := "0x00000000000000000000000000000000000000000000000000000000000f4240"
+	client, _ := newFakeClient(func(method string, params []json.RawMessage) (interface{}, *RPCError) {
+		if method != "eth_call" {
+			t.Fatalf("unexpected method %s", method)
+		}
+		var args map[string]interface{}
+		if err := json.Unmarshal(params[0], &args); err != nil {
+			t.Fatalf("invalid call args: %v", err)
+		}
+		if args["to"] != testTokenAddress || args["from"] != testOwner {
+			t.Errorf("call args = %v", args)
+		}
+		if _, ok := args["gas"]; ok {
+			t.Errorf("call args carry gas: %v", args)
+		}
+		if data, _ := args["data"].(string); !strings.HasPrefix(data, "0x70a08231") {
+			t.Errorf("data = %v, want balanceOf call", args["data"])
+		}
+		if string(params[1]) != `"latest"` {
+			t.Errorf("block = %s, want latest", params[1])
+		}
+		return balance, nil
+	})
+
+	data, err := NewERC20Token(testTokenAddress, "", "", 6).EncodeBalanceOf(testOwner)
+	if err != nil {
+		t.Fatalf("EncodeBalanceOf failed: %v", err)
+	}
+
+	result, err := client.Call("eth_call", NewCall(testTokenAddress, data).WithFrom(testOwner), "latest")
+	if err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+	if string(result) != `"`+balance+`"` {
+		t.Errorf("result = %s, want %s", result, balance)
+	}
+
+	if _, err := client.Call("eth_call", NewCall("0x1234", data), "latest"); err == nil {
+		t.Error("expected error for invalid call target")
+	}
+}
+
+// newHTTPTestClient serves canned JSON-RPC results keyed by method and records
+// the request ids it sees.
+func newHTTPTestClient(t *testing.T, results map[string]interface{}) (*Client, *[]uint64) {
+	t.Helper()
+	var ids []uint64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			JSONRPC string            `json:"jsonrpc"`
+			ID      uint64            `json:"id"`
+			Method  string            `json:"method"`
+			Params  []json.RawMessage `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.JSONRPC != "2.0" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		ids = append(ids, req.ID)
+
+		response := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+		if result, ok := results[req.Method]; ok {
+			response["result"] = result
+		} else {
+			response["error"] = map[string]interface{}{"code": -32601, "message": "the method " + req.Method + " does not exist"}
+		}
+		json.NewEncoder(w).Encode(response)
+	}))
+	t.Cleanup(server.Close)
+	return NewClient(server.URL), &ids
+}
+
+func TestHTTPClient(t *testing.T) {
+	txHash := "0x" + strings.Repeat("ab", 32)
+	client, ids := newHTTPTestClient(t, map[string]interface{}{
+		"web3_clientVersion":     "Geth/v1.13.0",
+		"eth_blockNumber":        "0x10d4f",
+		"eth_getBalance":         "0xde0b6b3a7640000",
+		"eth_sendRawTransaction": txHash,
+	})
+
+	version, err := client.Call("web3_clientVersion")
+	if err != nil || string(version) != `"Geth/v1.13.0"` {
+		t.Errorf("Call = %s, %v", version, err)
+	}
+
+	number, err := client.BlockNumber()
+	if err != nil || number.Int64() != 0x10d4f {
+		t.Errorf("BlockNumber = %v, %v, want 68943", number, err)
+	}
+
+	balance, err := client.GetBalance(testOwner, "")
+	if err != nil || balance.Cmp(big.NewInt(1e18)) != 0 {
+		t.Errorf("GetBalance = %v, %v, want 1e18", balance, err)
+	}
+
+	hash, err := client.SendRawTransaction("0xf86c098504a817c800825208")
+	if err != nil || hash != txHash {
+		t.Errorf("SendRawTransaction = %s, %v, want %s", hash, err, txHash)
+	}
+
+	if _, err := client.Call("eth_unknown"); err == nil {
+		t.Error("expected node error to surface")
+	} else if rpcErr, ok := err.(*RPCError); !ok || rpcErr.Code != -32601 {
+		t.Errorf("error = %v, want *RPCError -32601", err)
+	}
+
+	for i := 1; i < len(*ids); i++ {
+		if (*ids)[i] <= (*ids)[i-1] {
+			t.Fatalf("request ids not increasing: %v", *ids)
+		}
+	}
+}
+
+func TestHTTPClientStatusError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "rate limited", http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	if _, err := NewClient(server.URL).BlockNumber(); err == nil || !strings.Contains(err.Error(), "429") {
+		t.Fatalf("error = %v, want http status 429", err)
+	}
+}
+
+func TestEstimateGas(t *testing.T) {
+	client, _ := newFakeClient(func(method string, params []json.RawMessage) (interface{}, *RPCError) {
+		if method != "eth_estimateGas" {
+			t.Fatalf("unexpected method %s", method)
+		}
+		var args map[string]interface{}
+		json.Unmarshal(params[0], &args)
+		if args["from"] != testOwner || args["to"] != testSpender || args["value"] != "0xde0b6b3a7640000" {
+			t.Errorf("estimate args = %v", args)
+		}
+		return "0x5208", nil
+	})
+
+	gas, err := client.EstimateGas(&Transaction{
+		To:       testSpender,
+		Value:    big.NewInt(1e18),
+		GasPrice: big.NewInt(1),
+	}, testOwner)
+	if err != nil {
+		t.Fatalf("EstimateGas failed: %v", err)
+	}
+	if gas != 21000 {
+		t.Errorf("gas = %d, want 21000", gas)
+	}
+
+	if _, err := client.EstimateGas(&Transaction{To: testSpender}, "0x1234"); err == nil {
+		t.Error("expected error for invalid sender")
+	}
+}
+
+func TestSuggestGasPrice(t *testing.T) {
+	client, _ := newFakeClient(func(method string, params []json.RawMessage) (interface{}, *RPCError) {
+		if method != "eth_gasPrice" {
+			t.Fatalf("unexpected method %s", method)
+		}
+		return "0x6fc23ac00", nil // 30 gwei
+	})
+
+	price, err := client.SuggestGasPrice()
+	if err != nil {
+		t.Fatalf("SuggestGasPrice failed: %v", err)
+	}
+	if price.Int64() != 30_000_000_000 {
+		t.Errorf("gas price = %s, want 30 gwei", price)
+	}
+}
+
+func TestSuggestFeeData(t *testing.T) {
+	client, _ := newFakeClient(func(method string, params []json.RawMessage) (interface{}, *RPCError) {
+		switch method {
+		case "eth_feeHistory":
+			return map[string]interface{}{
+				"oldestBlock":   "0x1254bb1",
+				"baseFeePerGas": []string{"0x4a817c800", "0x4c5b6b2a6"}, // 20 gwei, then the pending block
+				"gasUsedRatio":  []float64{0.62},
+			}, nil
+		case "eth_maxPriorityFeePerGas":
+			return "0x59682f00", nil // 1.5 gwei
+		default:
+			t.Fatalf("unexpected method %s", method)
+			return nil, nil
+		}
+	})
+
+	fees, err := client.SuggestFeeData()
+	if err != nil {
+		t.Fatalf("SuggestFeeData failed: %v", err)
+	}
+
+	baseFee := big.NewInt(0x4c5b6b2a6)
+	if fees.BaseFee.Cmp(baseFee) != 0 {
+		t.Errorf("base fee = %s, want %s", fees.BaseFee, baseFee)
+	}
+	if fees.MaxPriorityFee.Int64() != 1_500_000_000 {
+		t.Errorf("max priority fee = %s, want 1.5 gwei", fees.MaxPriorityFee)
+	}
+	wantMaxFee := new(big.Int).Add(new(big.Int).Mul(baseFee, big.NewInt(2)), fees.MaxPriorityFee)
+	if fees.MaxFee.Cmp(wantMaxFee) != 0 {
+		t.Errorf("max fee = %s, want %s", fees.MaxFee, wantMaxFee)
+	}
+}
+
+func TestSuggestFeeDataPreLondon(t *testing.T) {
+	client, _ := newFakeClient(func(method string, params []json.RawMessage) (interface{}, *RPCError) {
+		return map[string]interface{}{"oldestBlock": "0x1", "baseFeePerGas": []string{}}, nil
+	})
+
+	if _, err := client.SuggestFeeData(); err == nil {
+		t.Fatal("expected error without base fees")
+	}
+}
+
+func TestReadBalanceOf(t *testing.T) {
+	client, _ := newFakeClient(func(method string, params []json.RawMessage) (interface{}, *RPCError) {
+		var args map[string]interface{}
+		json.Unmarshal(params[0], &args)
+		if method != "eth_call" || args["to"] != testTokenAddress {
+			t.Fatalf("unexpected call %s %v", method, args)
+		}
+		want := "0x70a08231000000000000000000000000" + strings.ToLower(testOwner[2:])
+		if args["data"] != want {
+			t.Errorf("data = %v, want %s", args["data"], want)
+		}
+		return testAmount1M, nil
+	})
+
+	balance, err := client.ReadBalanceOf(testTokenAddress, testOwner)
+	if err != nil {
+		t.Fatalf("ReadBalanceOf failed: %v", err)
+	}
+	if balance.Int64() != 1_000_000 {
+		t.Errorf("balance = %s, want 1000000", balance)
+	}
+}
+
+func TestCallContractEmptyReturn(t *testing.T) {
+	client, _ := newFakeClient(func(method string, params []json.RawMessage) (interface{}, *RPCError) {
+		return "0x", nil
+	})
+
+	_, err := client.CallContract(testTokenAddress, []byte{0x70, 0xa0, 0x82, 0x31}, "")
+	if err == nil || !strings.Contains(err.Error(), "returned no data") {
+		t.Fatalf("error = %v, want empty return error", err)
+	}
+	if _, err := client.ReadBalanceOf(testTokenAddress, testOwner); err == nil {
+		t.Error("expected ReadBalanceOf to fail on empty return")
+	}
+}
+
+func TestNonceAt(t *testing.T) {
+	nonces := map[string]string{"latest": "0x0", "pending": "0x1f"}
+	client, _ := newFakeClient(func(method string, params []json.RawMessage) (interface{}, *RPCError) {
+		var address, block string
+		json.Unmarshal(params[0], &address)
+		json.Unmarshal(params[1], &block)
+		if method != "eth_getTransactionCount" || address != testOwner {
+			t.Fatalf("unexpected call %s %s", method, params)
+		}
+		return nonces[block], nil
+	})
+
+	nonce, err := client.NonceAt(testOwner, "")
+	if err != nil {
+		t.Fatalf("NonceAt failed: %v", err)
+	}
+	if nonce != 0 {
+		t.Errorf("latest nonce = %d, want 0", nonce)
+	}
+
+	pending, err := client.PendingNonceAt(testOwner)
+	if err != nil {
+		t.Fatalf("PendingNonceAt failed: %v", err)
+	}
+	if pending != 31 {
+		t.Errorf("pending nonce = %d, want 31", pending)
+	}
+
+	if _, err := client.NonceAt("0x1234", "latest"); err == nil {
+		t.Error("expected error for invalid address")
+	}
+}
+
+func TestGetLogs(t *testing.T) {
+	client, _ := newFakeClient(func(method string, params []json.RawMessage) (interface{}, *RPCError) {
+		var query map[string]interface{}
+		json.Unmarshal(params[0], &query)
+		if method != "eth_getLogs" || query["fromBlock"] != "0x10" || query["toBlock"] != "latest" {
+			t.Fatalf("unexpected call %s %s", method, params)
+		}
+		log := stubReceipt("0x1")["logs"].([]map[string]interface{})[0]
+		return []map[string]interface{}{log, log}, nil
+	})
+
+	filter := NewEventFilter().AddAddress(testTokenAddress).SetFromBlock(big.NewInt(16)).SetLatestBlock()
+	events, err := client.GetLogs(filter)
+	if err != nil {
+		t.Fatalf("GetLogs failed: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want 2", len(events))
+	}
+	event := events[0]
+	if event.BlockNumber.Int64() != 0x12d687 || event.LogIndex != 7 || event.Data != testAmount1M || len(event.Topics) != 3 {
+		t.Errorf("event = %+v", event)
+	}
+}
+
+func TestGetLogsTooManyResults(t *testing.T) {
+	tests := []struct {
+		err  *RPCError
+		want bool
+	}{
+		{&RPCError{Code: -32005, Message: "limit exceeded"}, true},
+		{&RPCError{Code: -32000, Message: "query returned more than 10000 results"}, true},
+		{&RPCError{Code: -32602, Message: "Log response size exceeded. You can make eth_getLogs requests with up to a 2K block range"}, true},
+		{&RPCError{Code: -32000, Message: "rate limit exceeded"}, false},
+		{&RPCError{Code: -32000, Message: "gas required exceeds allowance: more than block gas limit"}, false},
+	}
+
+	for _, tt := range tests {
+		client, _ := newFakeClient(func(method string, params []json.RawMessage) (interface{}, *RPCError) {
+			return nil, tt.err
+		})
+		_, err := client.GetLogs(NewEventFilter())
+		if err == nil {
+			t.Fatalf("%q: expected error", tt.err.Message)
+		}
+		if got := errors.Is(err, ErrTooManyLogs); got != tt.want {
+			t.Errorf("%q: errors.Is(ErrTooManyLogs) = %v, want %v", tt.err.Message, got, tt.want)
+		}
+	}
+}
+
+func TestCheckAllowance(t *testing.T) {
+	client, _ := newFakeClient(func(method string, params []json.RawMessage) (interface{}, *RPCError) {
+		var args map[string]interface{}
+		json.Unmarshal(params[0], &args)
+		want := "0xdd62ed3e" +
+			"000000000000000000000000" + strings.ToLower(testOwner[2:]) +
+			"000000000000000000000000" + strings.ToLower(testSpender[2:])
+		if method != "eth_call" || args["to"] != testTokenAddress || args["data"] != want {
+			t.Fatalf("unexpected call %s %v", method, args)
+		}
+		return testAmount1M, nil
+	})
+
+	tests := []struct {
+		needed int64
+		want   bool
+	}{
+		{999_999, true},
+		{1_000_000, true},
+		{1_000_001, false},
+	}
+	for _, tt := range tests {
+		ok, allowance, err := client.CheckAllowance(context.Background(), testTokenAddress, testOwner, testSpender, big.NewInt(tt.needed))
+		if err != nil {
+			t.Fatalf("CheckAllowance(%d) failed: %v", tt.needed, err)
+		}
+		if ok != tt.want || allowance.Int64() != 1_000_000 {
+			t.Errorf("CheckAllowance(%d) = %v, %s; want %v, 1000000", tt.needed, ok, allowance, tt.want)
+		}
+	}
+
+	if _, _, err := client.CheckAllowance(context.Background(), testTokenAddress, testOwner, testSpender, big.NewInt(-1)); err == nil {
+		t.Error("expected error for a negative needed amount")
+	}
+}
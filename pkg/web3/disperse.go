@@ -0,0 +1,36 @@
+package web3
+
+import (
+	"fmt"
+	"math/big"
+)
+
+func EncodeDisperseToken(token string, recipients []string, amounts []*big.Int) ([]byte, error) {
+	if !ValidateAddress(token) {
+		return nil, fmt.Errorf("invalid token address")
+	}
+	if len(recipients) != len(amounts) {
+		return nil, fmt.Errorf("recipient count %d does not match amount count %d", len(recipients), len(amounts))
+	}
+
+	recipientValues := make([]interface{}, len(recipients))
+	amountValues := make([]interface{}, len(amounts))
+	for i, recipient := range recipients {
+		if !ValidateAddress(recipient) {
+			return nil, fmt.Errorf("invalid recipient address at index %d", i)
+		}
+		if amounts[i] == nil {
+			return nil, fmt.Errorf("nil amount at index %d", i)
+		}
+		recipientValues[i] = recipient
+		amountValues[i] = amounts[i]
+	}
+
+	params := []ABIParam{
+		{Name: "token", Type: "address"},
+		{Name: "recipients", Type: "address[]"},
+		{Name: "values", Type: "uint256[]"},
+	}
+
+	return EncodeFunctionCall("disperseToken", params, []interface{}{token, recipientValues, amountValues})
+}
@@ -0,0 +1,52 @@
+package web3
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+)
+
+func TestEncodeDisperseTokenLayout(t *testing.T) {
+	recipients := []string{
+		"0x1111111111111111111111111111111111111111",
+		"0x2222222222222222222222222222222222222222",
+		"0x3333333333333333333333333333333333333333",
+	}
+	amounts := []*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3)}
+
+	data, err := EncodeDisperseToken(testTokenAddress, recipients, amounts)
+	if err != nil {
+		t.Fatalf("EncodeDisperseToken failed: %v", err)
+	}
+
+	if got := hex.EncodeToString(data[:4]); got != "c73a2d60" {
+		t.Fatalf("selector = %s, want c73a2d60", got)
+	}
+
+	words := data[4:]
+	if len(words) != 11*32 {
+		t.Fatalf("payload length = %d, want %d", len(words), 11*32)
+	}
+	word := func(i int) *big.Int { return new(big.Int).SetBytes(words[i*32 : (i+1)*32]) }
+
+	if word(1).Int64() != 0x60 || word(2).Int64() != 0xe0 {
+		t.Errorf("array offsets = %#x, %#x, want 0x60, 0xe0", word(1), word(2))
+	}
+	if word(3).Int64() != 3 || word(7).Int64() != 3 {
+		t.Errorf("array lengths = %s, %s, want 3, 3", word(3), word(7))
+	}
+	for i, recipient := range recipients {
+		if got := "0x" + hex.EncodeToString(words[(4+i)*32+12:(5+i)*32]); got != recipient {
+			t.Errorf("recipient %d = %s, want %s", i, got, recipient)
+		}
+		if word(8+i).Cmp(amounts[i]) != 0 {
+			t.Errorf("amount %d = %s, want %s", i, word(8+i), amounts[i])
+		}
+	}
+}
+
+func TestEncodeDisperseTokenLengthMismatch(t *testing.T) {
+	if _, err := EncodeDisperseToken(testTokenAddress, []string{testOwner}, nil); err == nil {
+		t.Fatal("expected error for mismatched recipients and amounts")
+	}
+}
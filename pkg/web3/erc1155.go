@@ -0,0 +1,73 @@
+package web3
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+)
+
+const (
+	ERC1155_BALANCE_OF_SELECTOR       = "00fdd58e"
+	ERC1155_BALANCE_OF_BATCH_SELECTOR = "4e1273f4"
+)
+
+type ERC1155Token struct {
+	Address string
+}
+
+func NewERC1155Token(address string) *ERC1155Token {
+	return &ERC1155Token{
+		Address: address,
+	}
+}
+
+func (token *ERC1155Token) EncodeBalanceOfBatch(accounts []string, ids []*big.Int) ([]byte, error) {
+	if len(accounts) != len(ids) {
+		return nil, fmt.Errorf("account count %d does not match id count %d", len(accounts), len(ids))
+	}
+
+	accountValues := make([]interface{}, len(accounts))
+	idValues := make([]interface{}, len(ids))
+	for i, account := range accounts {
+		if !ValidateAddress(account) {
+			return nil, fmt.Errorf("invalid account address at index %d", i)
+		}
+		accountValues[i] = account
+		idValues[i] = ids[i]
+	}
+
+	params := []ABIParam{
+		{Name: "accounts", Type: "address[]"},
+		{Name: "ids", Type: "uint256[]"},
+	}
+	encodedParams, err := encodeParameters(params, []interface{}{accountValues, idValues})
+	if err != nil {
+		return nil, err
+	}
+
+	selector, _ := hex.DecodeString(ERC1155_BALANCE_OF_BATCH_SELECTOR)
+	return append(selector, encodedParams...), nil
+}
+
+func DecodeBalanceOfBatchResult(data []byte) ([]*big.Int, error) {
+	results, err := DecodeFunctionResult([]string{"uint256[]"}, data)
+	if err != nil {
+		return nil, err
+	}
+
+	values, ok := results[0].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected balance array type")
+	}
+
+	balances := make([]*big.Int, len(values))
+	for i, value := range values {
+		balance, ok := value.(*big.Int)
+		if !ok {
+			return nil, fmt.Errorf("unexpected balance type at index %d", i)
+		}
+		balances[i] = balance
+	}
+
+	return balances, nil
+}
@@ -0,0 +1,53 @@
+package web3
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestDecodeBalanceOfBatchResult(t *testing.T) {
+	data, _ := hex.DecodeString(strings.Join([]string{
+		"0000000000000000000000000000000000000000000000000000000000000020",
+		"0000000000000000000000000000000000000000000000000000000000000003",
+		"0000000000000000000000000000000000000000000000000000000000000001",
+		"0000000000000000000000000000000000000000000000000000000000000000",
+		"00000000000000000000000000000000000000000000000000000000000003e8",
+	}, ""))
+
+	balances, err := DecodeBalanceOfBatchResult(data)
+	if err != nil {
+		t.Fatalf("DecodeBalanceOfBatchResult failed: %v", err)
+	}
+
+	want := []int64{1, 0, 1000}
+	if len(balances) != len(want) {
+		t.Fatalf("got %d balances, want %d", len(balances), len(want))
+	}
+	for i, balance := range balances {
+		if balance.Int64() != want[i] {
+			t.Errorf("balance %d = %s, want %d", i, balance, want[i])
+		}
+	}
+}
+
+func TestDecodeArrayRejectsHugeLength(t *testing.T) {
+	tests := map[string]string{
+		"length 2^62": "0000000000000000000000000000000000000000000000000000000000000020" +
+			"0000000000000000000000000000000000000000000000000000000000000000"[:48] + "4000000000000000",
+		"offset near max int64": "0000000000000000000000000000000000000000000000007fffffffffffffff" +
+			"0000000000000000000000000000000000000000000000000000000000000001",
+	}
+
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			data, _ := hex.DecodeString(input)
+			if _, err := DecodeFunctionResult([]string{"uint256[]"}, data); err == nil {
+				t.Fatal("expected error")
+			}
+			if _, err := DecodeFunctionResult([]string{"bytes"}, data); err == nil {
+				t.Fatal("expected error for bytes")
+			}
+		})
+	}
+}
@@ -8,22 +8,57 @@ import (
 )
 
 const (
-	ERC20_TRANSFER_SELECTOR      = "a9059cbb"
-	ERC20_TRANSFER_FROM_SELECTOR = "23b872dd"
-	ERC20_APPROVE_SELECTOR       = "095ea7b3"
-	ERC20_BALANCE_OF_SELECTOR    = "70a08231"
-	ERC20_ALLOWANCE_SELECTOR     = "dd62ed3e"
-	ERC20_TOTAL_SUPPLY_SELECTOR  = "18160ddd"
-	ERC20_NAME_SELECTOR          = "06fdde03"
-	ERC20_SYMBOL_SELECTOR        = "95d89b41"
-	ERC20_DECIMALS_SELECTOR      = "313ce567"
+	ERC20_TRANSFER_SELECTOR         = "a9059cbb"
+	ERC20_TRANSFER_FROM_SELECTOR    = "23b872dd"
+	ERC20_APPROVE_SELECTOR          = "095ea7b3"
+	ERC20_BALANCE_OF_SELECTOR       = "70a08231"
+	ERC20_ALLOWANCE_SELECTOR        = "dd62ed3e"
+	ERC20_TOTAL_SUPPLY_SELECTOR     = "18160ddd"
+	ERC20_NAME_SELECTOR             = "06fdde03"
+	ERC20_SYMBOL_SELECTOR           = "95d89b41"
+	ERC20_DECIMALS_SELECTOR         = "313ce567"
+	ERC20_NONCES_SELECTOR           = "7ecebe00"
+	ERC20_DOMAIN_SEPARATOR_SELECTOR = "3644e515"
 )
 
+var erc20SelectorSignatures = []struct {
+	selector  string
+	signature string
+}{
+	{ERC20_TRANSFER_SELECTOR, "transfer(address,uint256)"},
+	{ERC20_TRANSFER_FROM_SELECTOR, "transferFrom(address,address,uint256)"},
+	{ERC20_APPROVE_SELECTOR, "approve(address,uint256)"},
+	{ERC20_BALANCE_OF_SELECTOR, "balanceOf(address)"},
+	{ERC20_ALLOWANCE_SELECTOR, "allowance(address,address)"},
+	{ERC20_TOTAL_SUPPLY_SELECTOR, "totalSupply()"},
+	{ERC20_NAME_SELECTOR, "name()"},
+	{ERC20_SYMBOL_SELECTOR, "symbol()"},
+	{ERC20_DECIMALS_SELECTOR, "decimals()"},
+	{ERC20_NONCES_SELECTOR, "nonces(address)"},
+	{ERC20_DOMAIN_SEPARATOR_SELECTOR, "DOMAIN_SEPARATOR()"},
+}
+
+const ZeroAddress = "0x0000000000000000000000000000000000000000"
+
 type ERC20Token struct {
-	Address  string
-	Name     string
-	Symbol   string
-	Decimals uint8
+	Address           string
+	Name              string
+	Symbol            string
+	Decimals          uint8
+	RejectZeroAddress bool
+}
+
+func VerifyERC20Selectors() error {
+	for _, entry := range erc20SelectorSignatures {
+		selector, err := FunctionSelector(entry.signature)
+		if err != nil {
+			return err
+		}
+		if computed := hex.EncodeToString(selector[:]); computed != entry.selector {
+			return fmt.Errorf("selector constant %s for %s does not match computed %s", entry.selector, entry.signature, computed)
+		}
+	}
+	return nil
 }
 
 func NewERC20Token(address, name, symbol string, decimals uint8) *ERC20Token {
@@ -36,14 +71,23 @@ func NewERC20Token(address, name, symbol string, decimals uint8) *ERC20Token {
 }
 
 func (token *ERC20Token) EncodeTransfer(to string, amount *big.Int) ([]byte, error) {
-	if !ValidateAddress(to) {
-		return nil, fmt.Errorf("invalid recipient address")
+	if token.RejectZeroAddress && IsZeroAddress(to) {
+		return nil, fmt.Errorf("transfer to zero address rejected, use EncodeBurnToZero for intentional burns")
 	}
+	return token.encodeTransfer(to, amount)
+}
 
+func (token *ERC20Token) EncodeBurnToZero(amount *big.Int) ([]byte, error) {
+	return token.encodeTransfer(ZeroAddress, amount)
+}
+
+func (token *ERC20Token) encodeTransfer(to string, amount *big.Int) ([]byte, error) {
 	selector, _ := hex.DecodeString(ERC20_TRANSFER_SELECTOR)
 
-	toAddress := strings.TrimPrefix(to, "0x")
-	toBytes, _ := hex.DecodeString(fmt.Sprintf("%064s", toAddress))
+	toBytes, err := encodeAddressWord(to)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recipient address: %w", err)
+	}
 
 	amountBytes := make([]byte, 32)
 	amount.FillBytes(amountBytes)
@@ -55,20 +99,17 @@ func (token *ERC20Token) EncodeTransfer(to string, amount *big.Int) ([]byte, err
 }
 
 func (token *ERC20Token) EncodeTransferFrom(from, to string, amount *big.Int) ([]byte, error) {
-	if !ValidateAddress(from) {
-		return nil, fmt.Errorf("invalid sender address")
-	}
-	if !ValidateAddress(to) {
-		return nil, fmt.Errorf("invalid recipient address")
-	}
-
 	selector, _ := hex.DecodeString(ERC20_TRANSFER_FROM_SELECTOR)
 
-	fromAddress := strings.TrimPrefix(from, "0x")
-	fromBytes, _ := hex.DecodeString(fmt.Sprintf("%064s", fromAddress))
+	fromBytes, err := encodeAddressWord(from)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender address: %w", err)
+	}
 
-	toAddress := strings.TrimPrefix(to, "0x")
-	toBytes, _ := hex.DecodeString(fmt.Sprintf("%064s", toAddress))
+	toBytes, err := encodeAddressWord(to)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recipient address: %w", err)
+	}
 
 	amountBytes := make([]byte, 32)
 	amount.FillBytes(amountBytes)
@@ -81,14 +122,12 @@ func (token *ERC20Token) EncodeTransferFrom(from, to string, amount *big.Int) ([
 }
 
 func (token *ERC20Token) EncodeApprove(spender string, amount *big.Int) ([]byte, error) {
-	if !ValidateAddress(spender) {
-		return nil, fmt.Errorf("invalid spender address")
-	}
-
 	selector, _ := hex.DecodeString(ERC20_APPROVE_SELECTOR)
 
-	spenderAddress := strings.TrimPrefix(spender, "0x")
-	spenderBytes, _ := hex.DecodeString(fmt.Sprintf("%064s", spenderAddress))
+	spenderBytes, err := encodeAddressWord(spender)
+	if err != nil {
+		return nil, fmt.Errorf("invalid spender address: %w", err)
+	}
 
 	amountBytes := make([]byte, 32)
 	amount.FillBytes(amountBytes)
@@ -99,15 +138,17 @@ func (token *ERC20Token) EncodeApprove(spender string, amount *big.Int) ([]byte,
 	return data, nil
 }
 
-func (token *ERC20Token) EncodeBalanceOf(owner string) ([]byte, error) {
-	if !ValidateAddress(owner) {
-		return nil, fmt.Errorf("invalid owner address")
-	}
+func (token *ERC20Token) EncodeApproveMax(spender string) ([]byte, error) {
+	return token.EncodeApprove(spender, MaxUint256())
+}
 
+func (token *ERC20Token) EncodeBalanceOf(owner string) ([]byte, error) {
 	selector, _ := hex.DecodeString(ERC20_BALANCE_OF_SELECTOR)
 
-	ownerAddress := strings.TrimPrefix(owner, "0x")
-	ownerBytes, _ := hex.DecodeString(fmt.Sprintf("%064s", ownerAddress))
+	ownerBytes, err := encodeAddressWord(owner)
+	if err != nil {
+		return nil, fmt.Errorf("invalid owner address: %w", err)
+	}
 
 	data := append(selector, ownerBytes...)
 
@@ -115,20 +156,17 @@ func (token *ERC20Token) EncodeBalanceOf(owner string) ([]byte, error) {
 }
 
 func (token *ERC20Token) EncodeAllowance(owner, spender string) ([]byte, error) {
-	if !ValidateAddress(owner) {
-		return nil, fmt.Errorf("invalid owner address")
-	}
-	if !ValidateAddress(spender) {
-		return nil, fmt.Errorf("invalid spender address")
-	}
-
 	selector, _ := hex.DecodeString(ERC20_ALLOWANCE_SELECTOR)
 
-	ownerAddress := strings.TrimPrefix(owner, "0x")
-	ownerBytes, _ := hex.DecodeString(fmt.Sprintf("%064s", ownerAddress))
+	ownerBytes, err := encodeAddressWord(owner)
+	if err != nil {
+		return nil, fmt.Errorf("invalid owner address: %w", err)
+	}
 
-	spenderAddress := strings.TrimPrefix(spender, "0x")
-	spenderBytes, _ := hex.DecodeString(fmt.Sprintf("%064s", spenderAddress))
+	spenderBytes, err := encodeAddressWord(spender)
+	if err != nil {
+		return nil, fmt.Errorf("invalid spender address: %w", err)
+	}
 
 	data := append(selector, ownerBytes...)
 	data = append(data, spenderBytes...)
@@ -141,18 +179,99 @@ func (token *ERC20Token) EncodeTotalSupply() ([]byte, error) {
 	return selector, nil
 }
 
-func (token *ERC20Token) DecodeTransferEvent(logData string, topics []string) (*TransferEvent, error) {
-	if len(topics) < 3 {
-		return nil, fmt.Errorf("insufficient topics for transfer event")
+func (token *ERC20Token) EncodeName() ([]byte, error) {
+	selector, _ := hex.DecodeString(ERC20_NAME_SELECTOR)
+	return selector, nil
+}
+
+func (token *ERC20Token) EncodeSymbol() ([]byte, error) {
+	selector, _ := hex.DecodeString(ERC20_SYMBOL_SELECTOR)
+	return selector, nil
+}
+
+func (token *ERC20Token) EncodeDecimals() ([]byte, error) {
+	selector, _ := hex.DecodeString(ERC20_DECIMALS_SELECTOR)
+	return selector, nil
+}
+
+func (token *ERC20Token) EncodeNonces(owner string) ([]byte, error) {
+	selector, _ := hex.DecodeString(ERC20_NONCES_SELECTOR)
+
+	ownerBytes, err := encodeAddressWord(owner)
+	if err != nil {
+		return nil, fmt.Errorf("invalid owner address: %w", err)
+	}
+
+	data := append(selector, ownerBytes...)
+
+	return data, nil
+}
+
+func (token *ERC20Token) EncodeDomainSeparator() ([]byte, error) {
+	selector, _ := hex.DecodeString(ERC20_DOMAIN_SEPARATOR_SELECTOR)
+	return selector, nil
+}
+
+func (token *ERC20Token) DecodeNonce(data []byte) (*big.Int, error) {
+	if len(data) < 32 {
+		return nil, fmt.Errorf("insufficient data for nonce")
 	}
+	return new(big.Int).SetBytes(data[:32]), nil
+}
 
-	from := "0x" + topics[1][26:]
-	to := "0x" + topics[2][26:]
+func (token *ERC20Token) DecodeDomainSeparator(data []byte) ([32]byte, error) {
+	var separator [32]byte
+	if len(data) < 32 {
+		return separator, fmt.Errorf("insufficient data for domain separator")
+	}
+	copy(separator[:], data[:32])
+	return separator, nil
+}
 
-	amount := new(big.Int)
-	if logData != "" && logData != "0x" {
-		amountHex := strings.TrimPrefix(logData, "0x")
-		amount.SetString(amountHex, 16)
+func (token *ERC20Token) DecodeTotalSupply(data []byte) (*big.Int, error) {
+	if len(data) < 32 {
+		return nil, fmt.Errorf("insufficient data for total supply")
+	}
+	return new(big.Int).SetBytes(data[:32]), nil
+}
+
+func (token *ERC20Token) DecodeDecimals(data []byte) (uint8, error) {
+	if len(data) < 32 {
+		return 0, fmt.Errorf("insufficient data for decimals")
+	}
+	decimals := new(big.Int).SetBytes(data[:32])
+	if !decimals.IsUint64() || decimals.Uint64() > 255 {
+		return 0, fmt.Errorf("decimals value %s does not fit in uint8", decimals.String())
+	}
+	return uint8(decimals.Uint64()), nil
+}
+
+func (token *ERC20Token) DecodeName(data []byte) (string, error) {
+	return decodeTokenString(data, "name")
+}
+
+func (token *ERC20Token) DecodeSymbol(data []byte) (string, error) {
+	return decodeTokenString(data, "symbol")
+}
+
+// Some early tokens (e.g. MKR) return name and symbol as bytes32 instead of
+// an ABI string, which is a single right-padded word.
+func decodeTokenString(data []byte, field string) (string, error) {
+	if len(data) == 32 {
+		return strings.TrimRight(string(data), "\x00"), nil
+	}
+
+	value, _, err := decodeString(data, 0)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s: %w", field, err)
+	}
+	return value, nil
+}
+
+func (token *ERC20Token) DecodeTransferEvent(logData string, topics []string) (*TransferEvent, error) {
+	from, to, amount, err := decodeERC20Event(logData, topics, ERC20_TRANSFER_SIGNATURE, "transfer")
+	if err != nil {
+		return nil, err
 	}
 
 	return &TransferEvent{
@@ -162,6 +281,57 @@ func (token *ERC20Token) DecodeTransferEvent(logData string, topics []string) (*
 	}, nil
 }
 
+func (token *ERC20Token) DecodeApprovalEvent(logData string, topics []string) (*ApprovalEvent, error) {
+	owner, spender, amount, err := decodeERC20Event(logData, topics, ERC20_APPROVAL_SIGNATURE, "approval")
+	if err != nil {
+		return nil, err
+	}
+
+	return &ApprovalEvent{
+		Owner:   owner,
+		Spender: spender,
+		Amount:  amount,
+	}, nil
+}
+
+func decodeERC20Event(logData string, topics []string, signature, name string) (string, string, *big.Int, error) {
+	if len(topics) != 3 {
+		return "", "", nil, fmt.Errorf("%s event must have 3 topics, got %d", name, len(topics))
+	}
+	if !strings.EqualFold(topics[0], signature) {
+		return "", "", nil, fmt.Errorf("topic %s is not a %s event", topics[0], name)
+	}
+
+	first, err := topicAddress(topics[1])
+	if err != nil {
+		return "", "", nil, fmt.Errorf("invalid %s topic 1: %w", name, err)
+	}
+	second, err := topicAddress(topics[2])
+	if err != nil {
+		return "", "", nil, fmt.Errorf("invalid %s topic 2: %w", name, err)
+	}
+
+	data, err := normalizeHex(logData)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("invalid %s data: %w", name, err)
+	}
+	if len(data) != 32 {
+		return "", "", nil, fmt.Errorf("%s data must be 32 bytes, got %d", name, len(data))
+	}
+
+	return first, second, new(big.Int).SetBytes(data), nil
+}
+
+func DecodeTransferEventFrom(token *ERC20Token, log Event) (*TransferEvent, error) {
+	if token == nil {
+		return nil, fmt.Errorf("token is required")
+	}
+	if !strings.EqualFold(log.Address, token.Address) {
+		return nil, fmt.Errorf("log address %s does not match token address %s", log.Address, token.Address)
+	}
+	return token.DecodeTransferEvent(log.Data, log.Topics)
+}
+
 func (token *ERC20Token) FormatAmount(amount *big.Int) string {
 	return FormatUnits(amount, int(token.Decimals))
 }
@@ -181,3 +351,66 @@ type ApprovalEvent struct {
 	Spender string
 	Amount  *big.Int
 }
+
+type CallDataClassification struct {
+	Selector  string
+	Method    string
+	Amount    *big.Int
+	Unlimited bool
+}
+
+func MaxUint256() *big.Int {
+	value := new(big.Int).Lsh(big.NewInt(1), 256)
+	return value.Sub(value, big.NewInt(1))
+}
+
+func SumAmounts(amounts []*big.Int) (*big.Int, error) {
+	limit := MaxUint256()
+	total := new(big.Int)
+	for i, amount := range amounts {
+		if amount == nil {
+			continue
+		}
+		if amount.Sign() < 0 {
+			return nil, fmt.Errorf("negative amount at index %d", i)
+		}
+		total.Add(total, amount)
+		if total.Cmp(limit) > 0 {
+			return nil, fmt.Errorf("sum overflows uint256 at index %d", i)
+		}
+	}
+	return total, nil
+}
+
+func ClassifyCallData(data []byte) (*CallDataClassification, error) {
+	if len(data) < 4 {
+		return nil, fmt.Errorf("call data too short for selector")
+	}
+
+	selector := hex.EncodeToString(data[:4])
+	classification := &CallDataClassification{Selector: selector}
+
+	var amountOffset int
+	switch selector {
+	case ERC20_TRANSFER_SELECTOR:
+		classification.Method = "transfer"
+		amountOffset = 4 + 32
+	case ERC20_TRANSFER_FROM_SELECTOR:
+		classification.Method = "transferFrom"
+		amountOffset = 4 + 64
+	case ERC20_APPROVE_SELECTOR:
+		classification.Method = "approve"
+		amountOffset = 4 + 32
+	default:
+		return classification, nil
+	}
+
+	if len(data) < amountOffset+32 {
+		return nil, fmt.Errorf("insufficient data for %s amount", classification.Method)
+	}
+
+	classification.Amount = new(big.Int).SetBytes(data[amountOffset : amountOffset+32])
+	classification.Unlimited = classification.Amount.Cmp(MaxUint256()) == 0
+
+	return classification, nil
+}
@@ -0,0 +1,265 @@
+package web3
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+const (
+	testTokenAddress = "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"
+	testOwner        = "0x742d35Cc6634c0532925a3B8d82C28d53e01bcF2"
+	testSpender      = "0x7a250d5630B4cF539739dF2C5dAcb4c659F2488D"
+)
+
+func TestEncodeApproveMax(t *testing.T) {
+	token := NewERC20Token(testTokenAddress, "USD Coin", "USDC", 6)
+
+	data, err := token.EncodeApproveMax(testSpender)
+	if err != nil {
+		t.Fatalf("EncodeApproveMax failed: %v", err)
+	}
+	if len(data) != 68 {
+		t.Fatalf("calldata length = %d, want 68", len(data))
+	}
+	if hex.EncodeToString(data[:4]) != ERC20_APPROVE_SELECTOR {
+		t.Errorf("selector = %x, want %s", data[:4], ERC20_APPROVE_SELECTOR)
+	}
+	if !bytes.Equal(data[36:], bytes.Repeat([]byte{0xff}, 32)) {
+		t.Errorf("amount word = %x, want all 0xff", data[36:])
+	}
+}
+
+func TestEncodeTransferRejectZeroAddress(t *testing.T) {
+	token := NewERC20Token(testTokenAddress, "USD Coin", "USDC", 6)
+
+	if _, err := token.EncodeTransfer(ZeroAddress, big.NewInt(1)); err != nil {
+		t.Fatalf("lenient token rejected zero address: %v", err)
+	}
+
+	token.RejectZeroAddress = true
+	if _, err := token.EncodeTransfer(ZeroAddress, big.NewInt(1)); err == nil {
+		t.Fatal("strict token accepted a transfer to the zero address")
+	}
+	if _, err := token.EncodeTransfer(testOwner, big.NewInt(1)); err != nil {
+		t.Fatalf("strict token rejected a normal recipient: %v", err)
+	}
+
+	data, err := token.EncodeBurnToZero(big.NewInt(1))
+	if err != nil {
+		t.Fatalf("EncodeBurnToZero failed: %v", err)
+	}
+	if !bytes.Equal(data[4:36], make([]byte, 32)) {
+		t.Errorf("burn recipient word = %x, want zero", data[4:36])
+	}
+}
+
+func TestEncodeNoncesAndDomainSeparator(t *testing.T) {
+	token := NewERC20Token(testTokenAddress, "USD Coin", "USDC", 6)
+
+	nonces, err := token.EncodeNonces(testOwner)
+	if err != nil {
+		t.Fatalf("EncodeNonces failed: %v", err)
+	}
+	if got := hex.EncodeToString(nonces[:4]); got != "7ecebe00" {
+		t.Errorf("nonces selector = %s, want 7ecebe00", got)
+	}
+	if len(nonces) != 36 {
+		t.Errorf("nonces calldata length = %d, want 36", len(nonces))
+	}
+
+	separator, err := token.EncodeDomainSeparator()
+	if err != nil {
+		t.Fatalf("EncodeDomainSeparator failed: %v", err)
+	}
+	if got := hex.EncodeToString(separator); got != "3644e515" {
+		t.Errorf("DOMAIN_SEPARATOR calldata = %s, want 3644e515", got)
+	}
+
+	word := make([]byte, 32)
+	word[31] = 7
+	nonce, err := token.DecodeNonce(word)
+	if err != nil || nonce.Int64() != 7 {
+		t.Errorf("DecodeNonce = %v, %v, want 7", nonce, err)
+	}
+
+	word[0] = 0xab
+	decoded, err := token.DecodeDomainSeparator(word)
+	if err != nil || !bytes.Equal(decoded[:], word) {
+		t.Errorf("DecodeDomainSeparator = %x, %v, want %x", decoded, err, word)
+	}
+}
+
+func TestDecodeTransferEventFromChecksContract(t *testing.T) {
+	token := NewERC20Token(testTokenAddress, "USD Coin", "USDC", 6)
+
+	log := transferLog(strings.ToLower(testTokenAddress))
+	transfer, err := DecodeTransferEventFrom(token, log)
+	if err != nil {
+		t.Fatalf("DecodeTransferEventFrom failed for matching contract: %v", err)
+	}
+	if transfer.Amount.Int64() != 1_000_000 {
+		t.Errorf("amount = %s, want 1000000", transfer.Amount)
+	}
+
+	log.Address = "0x6B175474E89094C44Da98b954EedeAC495271d0F"
+	_, err = DecodeTransferEventFrom(token, log)
+	if err == nil || !strings.Contains(err.Error(), "does not match token address") {
+		t.Fatalf("error = %v, want contract mismatch", err)
+	}
+}
+
+func TestSumAmountsBoundary(t *testing.T) {
+	almost := new(big.Int).Sub(MaxUint256(), big.NewInt(1))
+
+	total, err := SumAmounts([]*big.Int{almost, big.NewInt(1), nil})
+	if err != nil {
+		t.Fatalf("SumAmounts failed at the boundary: %v", err)
+	}
+	if total.Cmp(MaxUint256()) != 0 {
+		t.Errorf("total = %s, want 2^256-1", total)
+	}
+
+	if _, err := SumAmounts([]*big.Int{almost, big.NewInt(2)}); err == nil {
+		t.Error("expected overflow error above 2^256-1")
+	}
+	if _, err := SumAmounts([]*big.Int{big.NewInt(1), big.NewInt(-1)}); err == nil {
+		t.Error("expected error for negative amount")
+	}
+}
+
+func TestDecodeTransferEvent(t *testing.T) {
+	token := NewERC20Token(testTokenAddress, "USD Coin", "USDC", 6)
+
+	// A USDC transfer of 1.5 USDC as eth_getLogs returns it: 66-character
+	// topics and one uint256 data word.
+	topics := []string{
+		"0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef",
+		testTopicFrom,
+		testTopicTo,
+	}
+	for _, topic := range topics {
+		if len(topic) != 66 {
+			t.Fatalf("topic %s has %d characters, want 66", topic, len(topic))
+		}
+	}
+	data := "0x000000000000000000000000000000000000000000000000000000000016e360"
+
+	transfer, err := token.DecodeTransferEvent(data, topics)
+	if err != nil {
+		t.Fatalf("DecodeTransferEvent failed: %v", err)
+	}
+	if transfer.From != testOwner || transfer.To != testSpender {
+		t.Errorf("from %s to %s, want %s to %s", transfer.From, transfer.To, testOwner, testSpender)
+	}
+	if transfer.Amount.Int64() != 1_500_000 || token.FormatAmount(transfer.Amount) != "1.5" {
+		t.Errorf("amount = %s (%s), want 1500000 (1.5)", transfer.Amount, token.FormatAmount(transfer.Amount))
+	}
+
+	if _, err := token.DecodeTransferEvent(data, topics[:2]); err == nil {
+		t.Error("expected error for a missing topic")
+	}
+	if _, err := token.DecodeTransferEvent(data, []string{ERC20_APPROVAL_SIGNATURE, testTopicFrom, testTopicTo}); err == nil {
+		t.Error("expected error for an Approval topic")
+	}
+	if _, err := token.DecodeTransferEvent(data[:64], topics); err == nil {
+		t.Error("expected error for short data")
+	}
+}
+
+func TestVerifyERC20Selectors(t *testing.T) {
+	if err := VerifyERC20Selectors(); err != nil {
+		t.Fatalf("VerifyERC20Selectors failed: %v", err)
+	}
+
+	known := map[string]string{
+		ERC20_TRANSFER_SELECTOR:      "a9059cbb",
+		ERC20_TRANSFER_FROM_SELECTOR: "23b872dd",
+		ERC20_APPROVE_SELECTOR:       "095ea7b3",
+		ERC20_BALANCE_OF_SELECTOR:    "70a08231",
+		ERC20_ALLOWANCE_SELECTOR:     "dd62ed3e",
+		ERC20_TOTAL_SUPPLY_SELECTOR:  "18160ddd",
+	}
+	for constant, want := range known {
+		if constant != want {
+			t.Errorf("selector constant %s, want %s", constant, want)
+		}
+	}
+
+	original := erc20SelectorSignatures
+	defer func() { erc20SelectorSignatures = original }()
+	erc20SelectorSignatures = append(append(erc20SelectorSignatures[:0:0], original...), struct {
+		selector  string
+		signature string
+	}{"a9059cbb", "transfer(address,uint128)"})
+	if err := VerifyERC20Selectors(); err == nil || !strings.Contains(err.Error(), "transfer(address,uint128)") {
+		t.Errorf("error = %v, want mismatch for transfer(address,uint128)", err)
+	}
+}
+
+func TestDecodeDecimalsAndName(t *testing.T) {
+	token := NewERC20Token(testTokenAddress, "", "", 0)
+
+	decimals, err := token.DecodeDecimals(mustDecodeHex(t, "0000000000000000000000000000000000000000000000000000000000000006"))
+	if err != nil {
+		t.Fatalf("DecodeDecimals failed: %v", err)
+	}
+	if decimals != 6 {
+		t.Errorf("decimals = %d, want 6", decimals)
+	}
+	if _, err := token.DecodeDecimals(mustDecodeHex(t, "0000000000000000000000000000000000000000000000000000000000000100")); err == nil {
+		t.Error("expected error for decimals of 256")
+	}
+	if _, err := token.DecodeDecimals([]byte{6}); err == nil {
+		t.Error("expected error for a short decimals word")
+	}
+
+	name, err := token.DecodeName(mustDecodeHex(t, ""+
+		"0000000000000000000000000000000000000000000000000000000000000020"+
+		"0000000000000000000000000000000000000000000000000000000000000008"+
+		"55534420436f696e000000000000000000000000000000000000000000000000"))
+	if err != nil {
+		t.Fatalf("DecodeName failed: %v", err)
+	}
+	if name != "USD Coin" {
+		t.Errorf("name = %q, want USD Coin", name)
+	}
+
+	// MKR returns its symbol as bytes32.
+	symbol, err := token.DecodeSymbol(mustDecodeHex(t, "4d4b520000000000000000000000000000000000000000000000000000000000"))
+	if err != nil {
+		t.Fatalf("DecodeSymbol failed: %v", err)
+	}
+	if symbol != "MKR" {
+		t.Errorf("symbol = %q, want MKR", symbol)
+	}
+}
+
+func TestDecodeApprovalEvent(t *testing.T) {
+	token := NewERC20Token(testTokenAddress, "USD Coin", "USDC", 6)
+
+	// An unlimited approval to the Uniswap V2 router.
+	topics := []string{
+		"0x8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925",
+		testTopicFrom,
+		testTopicTo,
+	}
+	data := "0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"
+
+	approval, err := token.DecodeApprovalEvent(data, topics)
+	if err != nil {
+		t.Fatalf("DecodeApprovalEvent failed: %v", err)
+	}
+	if approval.Owner != testOwner || approval.Spender != testSpender {
+		t.Errorf("owner %s spender %s, want %s and %s", approval.Owner, approval.Spender, testOwner, testSpender)
+	}
+	if approval.Amount.Cmp(MaxUint256()) != 0 {
+		t.Errorf("amount = %s, want max uint256", approval.Amount)
+	}
+
+	if _, err := token.DecodeApprovalEvent(data, []string{ERC20_TRANSFER_SIGNATURE, testTopicFrom, testTopicTo}); err == nil {
+		t.Error("expected error for a Transfer topic")
+	}
+}
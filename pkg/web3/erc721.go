@@ -4,7 +4,6 @@ import (
 	"encoding/hex"
 	"fmt"
 	"math/big"
-	"strings"
 )
 
 const (
@@ -36,20 +35,17 @@ func NewERC721Token(address, name, symbol string) *ERC721Token {
 }
 
 func (nft *ERC721Token) EncodeTransferFrom(from, to string, tokenId *big.Int) ([]byte, error) {
-	if !ValidateAddress(from) {
-		return nil, fmt.Errorf("invalid sender address")
-	}
-	if !ValidateAddress(to) {
-		return nil, fmt.Errorf("invalid recipient address")
-	}
-
 	selector, _ := hex.DecodeString(ERC721_TRANSFER_FROM_SELECTOR)
 
-	fromAddress := strings.TrimPrefix(from, "0x")
-	fromBytes, _ := hex.DecodeString(fmt.Sprintf("%064s", fromAddress))
+	fromBytes, err := encodeAddressWord(from)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender address: %w", err)
+	}
 
-	toAddress := strings.TrimPrefix(to, "0x")
-	toBytes, _ := hex.DecodeString(fmt.Sprintf("%064s", toAddress))
+	toBytes, err := encodeAddressWord(to)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recipient address: %w", err)
+	}
 
 	tokenIdBytes := make([]byte, 32)
 	tokenId.FillBytes(tokenIdBytes)
@@ -62,20 +58,17 @@ func (nft *ERC721Token) EncodeTransferFrom(from, to string, tokenId *big.Int) ([
 }
 
 func (nft *ERC721Token) EncodeSafeTransferFrom(from, to string, tokenId *big.Int, data []byte) ([]byte, error) {
-	if !ValidateAddress(from) {
-		return nil, fmt.Errorf("invalid sender address")
-	}
-	if !ValidateAddress(to) {
-		return nil, fmt.Errorf("invalid recipient address")
-	}
-
 	selector, _ := hex.DecodeString(ERC721_SAFE_TRANSFER_FROM_SELECTOR)
 
-	fromAddress := strings.TrimPrefix(from, "0x")
-	fromBytes, _ := hex.DecodeString(fmt.Sprintf("%064s", fromAddress))
+	fromBytes, err := encodeAddressWord(from)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender address: %w", err)
+	}
 
-	toAddress := strings.TrimPrefix(to, "0x")
-	toBytes, _ := hex.DecodeString(fmt.Sprintf("%064s", toAddress))
+	toBytes, err := encodeAddressWord(to)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recipient address: %w", err)
+	}
 
 	tokenIdBytes := make([]byte, 32)
 	tokenId.FillBytes(tokenIdBytes)
@@ -95,14 +88,12 @@ func (nft *ERC721Token) EncodeSafeTransferFrom(from, to string, tokenId *big.Int
 }
 
 func (nft *ERC721Token) EncodeApprove(to string, tokenId *big.Int) ([]byte, error) {
-	if !ValidateAddress(to) {
-		return nil, fmt.Errorf("invalid recipient address")
-	}
-
 	selector, _ := hex.DecodeString(ERC721_APPROVE_SELECTOR)
 
-	toAddress := strings.TrimPrefix(to, "0x")
-	toBytes, _ := hex.DecodeString(fmt.Sprintf("%064s", toAddress))
+	toBytes, err := encodeAddressWord(to)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recipient address: %w", err)
+	}
 
 	tokenIdBytes := make([]byte, 32)
 	tokenId.FillBytes(tokenIdBytes)
@@ -114,14 +105,12 @@ func (nft *ERC721Token) EncodeApprove(to string, tokenId *big.Int) ([]byte, erro
 }
 
 func (nft *ERC721Token) EncodeSetApprovalForAll(operator string, approved bool) ([]byte, error) {
-	if !ValidateAddress(operator) {
-		return nil, fmt.Errorf("invalid operator address")
-	}
-
 	selector, _ := hex.DecodeString(ERC721_SET_APPROVAL_FOR_ALL_SELECTOR)
 
-	operatorAddress := strings.TrimPrefix(operator, "0x")
-	operatorBytes, _ := hex.DecodeString(fmt.Sprintf("%064s", operatorAddress))
+	operatorBytes, err := encodeAddressWord(operator)
+	if err != nil {
+		return nil, fmt.Errorf("invalid operator address: %w", err)
+	}
 
 	approvedBytes := make([]byte, 32)
 	if approved {
@@ -146,14 +135,12 @@ func (nft *ERC721Token) EncodeOwnerOf(tokenId *big.Int) ([]byte, error) {
 }
 
 func (nft *ERC721Token) EncodeBalanceOf(owner string) ([]byte, error) {
-	if !ValidateAddress(owner) {
-		return nil, fmt.Errorf("invalid owner address")
-	}
-
 	selector, _ := hex.DecodeString(ERC721_BALANCE_OF_SELECTOR)
 
-	ownerAddress := strings.TrimPrefix(owner, "0x")
-	ownerBytes, _ := hex.DecodeString(fmt.Sprintf("%064s", ownerAddress))
+	ownerBytes, err := encodeAddressWord(owner)
+	if err != nil {
+		return nil, fmt.Errorf("invalid owner address: %w", err)
+	}
 
 	data := append(selector, ownerBytes...)
 
@@ -172,20 +159,17 @@ func (nft *ERC721Token) EncodeGetApproved(tokenId *big.Int) ([]byte, error) {
 }
 
 func (nft *ERC721Token) EncodeIsApprovedForAll(owner, operator string) ([]byte, error) {
-	if !ValidateAddress(owner) {
-		return nil, fmt.Errorf("invalid owner address")
-	}
-	if !ValidateAddress(operator) {
-		return nil, fmt.Errorf("invalid operator address")
-	}
-
 	selector, _ := hex.DecodeString(ERC721_IS_APPROVED_FOR_ALL_SELECTOR)
 
-	ownerAddress := strings.TrimPrefix(owner, "0x")
-	ownerBytes, _ := hex.DecodeString(fmt.Sprintf("%064s", ownerAddress))
+	ownerBytes, err := encodeAddressWord(owner)
+	if err != nil {
+		return nil, fmt.Errorf("invalid owner address: %w", err)
+	}
 
-	operatorAddress := strings.TrimPrefix(operator, "0x")
-	operatorBytes, _ := hex.DecodeString(fmt.Sprintf("%064s", operatorAddress))
+	operatorBytes, err := encodeAddressWord(operator)
+	if err != nil {
+		return nil, fmt.Errorf("invalid operator address: %w", err)
+	}
 
 	data := append(selector, ownerBytes...)
 	data = append(data, operatorBytes...)
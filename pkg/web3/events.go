@@ -2,8 +2,10 @@ package web3
 
 import (
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"math/big"
+	"sort"
 	"strings"
 	"time"
 )
@@ -13,6 +15,8 @@ type EventFilter struct {
 	ToBlock   *big.Int
 	Address   []string
 	Topics    [][]string
+
+	err error
 }
 
 type EventSubscription struct {
@@ -21,6 +25,8 @@ type EventSubscription struct {
 	Channel   chan Event
 	Active    bool
 	CreatedAt time.Time
+
+	unsubscribe func()
 }
 
 type Event struct {
@@ -71,33 +77,143 @@ func (f *EventFilter) AddAddress(address string) *EventFilter {
 	return f
 }
 
+func (f *EventFilter) SetEventSignature(signature string) *EventFilter {
+	if !isTopicHex(signature) {
+		f.setError(fmt.Errorf("invalid event signature topic: %s", signature))
+		return f
+	}
+	if len(f.Topics) == 0 {
+		f.Topics = append(f.Topics, []string{})
+	}
+	f.Topics[0] = []string{strings.ToLower(signature)}
+	return f
+}
+
+// AddTopic ORs another event signature into topic 0, for filters that match
+// several event types at once.
 func (f *EventFilter) AddTopic(topic string) *EventFilter {
+	if !isTopicHex(topic) {
+		f.setError(fmt.Errorf("invalid event signature topic: %s", topic))
+		return f
+	}
 	if len(f.Topics) == 0 {
 		f.Topics = append(f.Topics, []string{})
 	}
-	f.Topics[0] = append(f.Topics[0], topic)
+	f.Topics[0] = append(f.Topics[0], strings.ToLower(topic))
 	return f
 }
 
 func (f *EventFilter) AddIndexedParameter(index int, value string) *EventFilter {
+	if index < 1 || index > 3 {
+		f.setError(fmt.Errorf("indexed parameter position %d must be between 1 and 3", index))
+		return f
+	}
+	if !isTopicHex(value) {
+		f.setError(fmt.Errorf("invalid topic at position %d: %s", index, value))
+		return f
+	}
 	for len(f.Topics) <= index {
 		f.Topics = append(f.Topics, []string{})
 	}
-	f.Topics[index] = append(f.Topics[index], value)
+	f.Topics[index] = append(f.Topics[index], strings.ToLower(value))
 	return f
 }
 
-func CreateEventSignature(eventName string, paramTypes []string) string {
-	signature := eventName + "(" + strings.Join(paramTypes, ",") + ")"
-	return "0x" + Keccak256([]byte(signature))
+func (f *EventFilter) MatchEvent(eventSig string) *EventFilter {
+	open := strings.Index(eventSig, "(")
+	if open <= 0 || !strings.HasSuffix(eventSig, ")") || strings.ContainsAny(eventSig, " \t") {
+		f.setError(fmt.Errorf("invalid event signature: %s", eventSig))
+		return f
+	}
+	return f.SetEventSignature("0x" + Keccak256([]byte(eventSig)))
 }
 
-func Keccak256(data []byte) string {
-	hash := make([]byte, 32)
-	for i, b := range data {
-		hash[i%32] ^= b
+func (f *EventFilter) AddIndexedAddress(index int, address string) *EventFilter {
+	topic, err := EncodeTopicAddress(address)
+	if err != nil {
+		f.setError(fmt.Errorf("invalid indexed address at position %d: %w", index, err))
+		return f
+	}
+	return f.AddIndexedParameter(index, topic)
+}
+
+func EncodeTopicAddress(addr string) (string, error) {
+	if !ValidateAddress(addr) {
+		return "", fmt.Errorf("invalid address: %s", addr)
+	}
+	return "0x" + strings.Repeat("0", 24) + strings.ToLower(addr[2:]), nil
+}
+
+func topicAddress(topic string) (string, error) {
+	if !isTopicHex(topic) {
+		return "", fmt.Errorf("topic must be 32 bytes of 0x-prefixed hex, got %q", topic)
+	}
+	if strings.Trim(topic[2:26], "0") != "" {
+		return "", fmt.Errorf("topic %s has non-zero address padding", topic)
+	}
+	return ToChecksumAddress("0x" + topic[26:])
+}
+
+func (f *EventFilter) Validate() error {
+	if f.err != nil {
+		return f.err
+	}
+	if len(f.Topics) > 4 {
+		return fmt.Errorf("filter has %d topic positions, at most 4 are allowed", len(f.Topics))
+	}
+	for _, address := range f.Address {
+		if !ValidateAddress(address) {
+			return fmt.Errorf("invalid filter address: %s", address)
+		}
+	}
+	for i, position := range f.Topics {
+		for _, topic := range position {
+			if !isTopicHex(topic) {
+				return fmt.Errorf("invalid topic at position %d: %s", i, topic)
+			}
+		}
 	}
-	return hex.EncodeToString(hash)
+	return nil
+}
+
+func (f *EventFilter) setError(err error) {
+	if f.err == nil {
+		f.err = err
+	}
+}
+
+func isTopicHex(topic string) bool {
+	if len(topic) != 66 || !strings.HasPrefix(topic, "0x") {
+		return false
+	}
+	_, err := hex.DecodeString(topic[2:])
+	return err == nil
+}
+
+func logFilterParams(filter *EventFilter) map[string]interface{} {
+	params := make(map[string]interface{})
+	if filter == nil {
+		return params
+	}
+
+	if len(filter.Address) > 0 {
+		params["address"] = filter.Address
+	}
+	if len(filter.Topics) > 0 {
+		topics := make([]interface{}, len(filter.Topics))
+		for i, position := range filter.Topics {
+			if len(position) > 0 {
+				topics[i] = position
+			}
+		}
+		params["topics"] = topics
+	}
+	return params
+}
+
+func CreateEventSignature(eventName string, paramTypes []string) string {
+	signature := eventName + "(" + strings.Join(paramTypes, ",") + ")"
+	return "0x" + Keccak256([]byte(signature))
 }
 
 func ParseTransferEvent(log Event) (*TransferEvent, error) {
@@ -105,14 +221,23 @@ func ParseTransferEvent(log Event) (*TransferEvent, error) {
 		return nil, fmt.Errorf("insufficient topics for transfer event")
 	}
 
-	from := "0x" + log.Topics[1][26:]
-	to := "0x" + log.Topics[2][26:]
+	from, err := topicAddress(log.Topics[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid transfer topic 1: %w", err)
+	}
+	to, err := topicAddress(log.Topics[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid transfer topic 2: %w", err)
+	}
 
-	amount := new(big.Int)
-	if log.Data != "" && log.Data != "0x" {
-		amountHex := strings.TrimPrefix(log.Data, "0x")
-		amount.SetString(amountHex, 16)
+	data, err := normalizeHex(log.Data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid transfer data: %w", err)
 	}
+	if len(data) > 32 {
+		return nil, fmt.Errorf("transfer data must be at most 32 bytes, got %d", len(data))
+	}
+	amount := new(big.Int).SetBytes(data)
 
 	return &TransferEvent{
 		From:   from,
@@ -126,11 +251,20 @@ func ParseNFTTransferEvent(log Event) (*NFTTransferEvent, error) {
 		return nil, fmt.Errorf("insufficient topics for NFT transfer event")
 	}
 
-	from := "0x" + log.Topics[1][26:]
-	to := "0x" + log.Topics[2][26:]
+	from, err := topicAddress(log.Topics[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid transfer topic 1: %w", err)
+	}
+	to, err := topicAddress(log.Topics[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid transfer topic 2: %w", err)
+	}
 
-	tokenId := new(big.Int)
-	tokenId.SetString(log.Topics[3][2:], 16)
+	tokenIdBytes, err := normalizeHex(log.Topics[3])
+	if err != nil || len(tokenIdBytes) != 32 {
+		return nil, fmt.Errorf("invalid token id topic %q", log.Topics[3])
+	}
+	tokenId := new(big.Int).SetBytes(tokenIdBytes)
 
 	return &NFTTransferEvent{
 		From:    from,
@@ -149,12 +283,98 @@ func CreateEventSubscription(filter *EventFilter) *EventSubscription {
 	}
 }
 
+func CreateDeterministicEventSubscription(filter *EventFilter) *EventSubscription {
+	return RestoreSubscription(DeterministicSubscriptionID(filter), filter)
+}
+
+// DeterministicSubscriptionID hashes a canonical form of the filter, so
+// address and topic casing or ordering within a position do not change it.
+func DeterministicSubscriptionID(filter *EventFilter) string {
+	canonical := struct {
+		FromBlock string     `json:"fromBlock"`
+		ToBlock   string     `json:"toBlock"`
+		Address   []string   `json:"address"`
+		Topics    [][]string `json:"topics"`
+	}{
+		Address: []string{},
+		Topics:  [][]string{},
+	}
+
+	if filter != nil {
+		if filter.FromBlock != nil {
+			canonical.FromBlock = filter.FromBlock.String()
+		}
+		if filter.ToBlock != nil {
+			canonical.ToBlock = filter.ToBlock.String()
+		}
+		canonical.Address = sortedLower(filter.Address)
+		for _, topics := range filter.Topics {
+			canonical.Topics = append(canonical.Topics, sortedLower(topics))
+		}
+	}
+
+	serialized, _ := json.Marshal(canonical)
+	return "sub_" + Keccak256(serialized)
+}
+
+func sortedLower(values []string) []string {
+	result := make([]string, len(values))
+	for i, value := range values {
+		result[i] = strings.ToLower(value)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func RestoreSubscription(id string, filter *EventFilter) *EventSubscription {
+	return &EventSubscription{
+		ID:        id,
+		Filter:    copyEventFilter(filter),
+		Channel:   make(chan Event, 100),
+		Active:    true,
+		CreatedAt: time.Now(),
+	}
+}
+
+func (sub *EventSubscription) Config() *EventFilter {
+	return copyEventFilter(sub.Filter)
+}
+
+func copyEventFilter(filter *EventFilter) *EventFilter {
+	if filter == nil {
+		return nil
+	}
+
+	copied := &EventFilter{err: filter.err}
+	if filter.FromBlock != nil {
+		copied.FromBlock = new(big.Int).Set(filter.FromBlock)
+	}
+	if filter.ToBlock != nil {
+		copied.ToBlock = new(big.Int).Set(filter.ToBlock)
+	}
+	if filter.Address != nil {
+		copied.Address = append([]string{}, filter.Address...)
+	}
+	if filter.Topics != nil {
+		copied.Topics = make([][]string, len(filter.Topics))
+		for i, topics := range filter.Topics {
+			if topics != nil {
+				copied.Topics[i] = append([]string{}, topics...)
+			}
+		}
+	}
+	return copied
+}
+
 func generateSubscriptionID() string {
 	timestamp := time.Now().UnixNano()
 	return fmt.Sprintf("sub_%d", timestamp)
 }
 
 func (sub *EventSubscription) Stop() {
+	if sub.unsubscribe != nil {
+		sub.unsubscribe()
+	}
 	sub.Active = false
 	close(sub.Channel)
 }
@@ -166,21 +386,55 @@ func (sub *EventSubscription) GetEvents() <-chan Event {
 type EventMonitor struct {
 	subscriptions map[string]*EventSubscription
 	handlers      map[string][]EventHandler
+	synchronous   bool
+	errors        chan error
 }
 
 func NewEventMonitor() *EventMonitor {
 	return &EventMonitor{
 		subscriptions: make(map[string]*EventSubscription),
 		handlers:      make(map[string][]EventHandler),
+		errors:        make(chan error, 100),
 	}
 }
 
+func (em *EventMonitor) SetSynchronous(synchronous bool) *EventMonitor {
+	em.synchronous = synchronous
+	return em
+}
+
+func (em *EventMonitor) Errors() <-chan error {
+	return em.errors
+}
+
 func (em *EventMonitor) Subscribe(filter *EventFilter) *EventSubscription {
 	sub := CreateEventSubscription(filter)
 	em.subscriptions[sub.ID] = sub
 	return sub
 }
 
+// SubscribeChecked is Subscribe for filters built from untrusted input: it
+// refuses filters whose setters recorded a bad topic or address, since
+// registering them without the rejected restriction would match too much.
+func (em *EventMonitor) SubscribeChecked(filter *EventFilter) (*EventSubscription, error) {
+	if filter == nil {
+		return nil, fmt.Errorf("filter is required")
+	}
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	return em.Subscribe(filter), nil
+}
+
+// RestoreSubscription registers a subscription persisted with Config under
+// its original id, replacing any subscription already using that id.
+func (em *EventMonitor) RestoreSubscription(id string, filter *EventFilter) *EventSubscription {
+	em.Unsubscribe(id)
+	sub := RestoreSubscription(id, filter)
+	em.subscriptions[sub.ID] = sub
+	return sub
+}
+
 func (em *EventMonitor) Unsubscribe(subscriptionID string) {
 	if sub, exists := em.subscriptions[subscriptionID]; exists {
 		sub.Stop()
@@ -209,12 +463,75 @@ func (em *EventMonitor) ProcessEvent(event Event) {
 		eventSignature := event.Topics[0]
 		if handlers, exists := em.handlers[eventSignature]; exists {
 			for _, handler := range handlers {
-				go handler(event)
+				if em.synchronous {
+					em.runHandler(handler, event)
+				} else {
+					go em.runHandler(handler, event)
+				}
 			}
 		}
 	}
 }
 
+func (em *EventMonitor) runHandler(handler EventHandler, event Event) {
+	var err error
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("event handler panicked: %v", r)
+		}
+		if err != nil {
+			em.reportError(err)
+		}
+	}()
+
+	err = handler(event)
+}
+
+func (em *EventMonitor) reportError(err error) {
+	select {
+	case em.errors <- err:
+	default:
+	}
+}
+
+// WatchTransfers decodes on its own goroutine until the returned subscription
+// is stopped with Unsubscribe. Logs that fail to decode are reported on Errors.
+func (em *EventMonitor) WatchTransfers(contracts []string, handler func(*TransferEvent, Event)) (*EventSubscription, error) {
+	if len(contracts) == 0 {
+		return nil, fmt.Errorf("at least one contract address is required")
+	}
+	if handler == nil {
+		return nil, fmt.Errorf("handler is required")
+	}
+
+	filter := NewEventFilter()
+	for _, contract := range contracts {
+		if !ValidateAddress(contract) {
+			return nil, fmt.Errorf("invalid contract address: %s", contract)
+		}
+		filter.AddAddress(contract)
+	}
+	filter.SetEventSignature(ERC20_TRANSFER_SIGNATURE)
+
+	sub := em.Subscribe(filter)
+
+	go func() {
+		for event := range sub.GetEvents() {
+			from, to, amount, err := decodeERC20Event(event.Data, event.Topics, ERC20_TRANSFER_SIGNATURE, "transfer")
+			if err != nil {
+				em.reportError(fmt.Errorf("failed to decode transfer from %s: %w", event.Address, err))
+				continue
+			}
+			em.runHandler(func(event Event) error {
+				handler(&TransferEvent{From: from, To: to, Amount: amount}, event)
+				return nil
+			}, event)
+		}
+	}()
+
+	return sub, nil
+}
+
 func (em *EventMonitor) eventMatchesFilter(event Event, filter *EventFilter) bool {
 	if len(filter.Address) > 0 {
 		addressMatch := false
@@ -0,0 +1,272 @@
+package web3
+
+import (
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	testTopicFrom = "0x000000000000000000000000742d35cc6634c0532925a3b8d82c28d53e01bcf2"
+	testTopicTo   = "0x0000000000000000000000007a250d5630b4cf539739df2c5dacb4c659f2488d"
+	testAmount1M  = "0x00000000000000000000000000000000000000000000000000000000000f4240"
+)
+
+func transferLog(contract string) Event {
+	return Event{
+		Address: contract,
+		Topics:  []string{ERC20_TRANSFER_SIGNATURE, testTopicFrom, testTopicTo},
+		Data:    testAmount1M,
+	}
+}
+
+func TestWatchTransfersTwoContracts(t *testing.T) {
+	usdc := "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"
+	dai := "0x6B175474E89094C44Da98b954EedeAC495271d0F"
+	other := "0xdAC17F958D2ee523a2206206994597C13D831ec7"
+
+	monitor := NewEventMonitor()
+	seen := make(chan string, 10)
+	sub, err := monitor.WatchTransfers([]string{usdc, dai}, func(transfer *TransferEvent, event Event) {
+		if transfer.From != testOwner || transfer.Amount.Int64() != 1_000_000 {
+			t.Errorf("unexpected transfer %+v", transfer)
+		}
+		seen <- event.Address
+	})
+	if err != nil {
+		t.Fatalf("WatchTransfers failed: %v", err)
+	}
+
+	monitor.ProcessEvent(transferLog(usdc))
+	monitor.ProcessEvent(transferLog(other))
+	monitor.ProcessEvent(transferLog(dai))
+
+	var got []string
+	for len(got) < 2 {
+		select {
+		case address := <-seen:
+			got = append(got, address)
+		case <-time.After(time.Second):
+			t.Fatalf("handler fired %d times, want 2", len(got))
+		}
+	}
+	sort.Strings(got)
+	if got[0] != dai || got[1] != usdc {
+		t.Errorf("handler saw %v, want %s and %s", got, usdc, dai)
+	}
+
+	monitor.Unsubscribe(sub.ID)
+	monitor.ProcessEvent(transferLog(usdc))
+	select {
+	case address := <-seen:
+		t.Errorf("handler fired for %s after unsubscribe", address)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestWatchTransfersReportsMalformedLogs(t *testing.T) {
+	usdc := "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"
+	monitor := NewEventMonitor()
+	sub, err := monitor.WatchTransfers([]string{usdc}, func(*TransferEvent, Event) {
+		t.Error("handler fired for a malformed log")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer monitor.Unsubscribe(sub.ID)
+
+	monitor.ProcessEvent(Event{
+		Address: usdc,
+		Topics:  []string{ERC20_TRANSFER_SIGNATURE, "0x01", testTopicTo},
+		Data:    testAmount1M,
+	})
+
+	select {
+	case err := <-monitor.Errors():
+		if err == nil {
+			t.Fatal("expected decode error")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("malformed log was not reported")
+	}
+}
+
+func TestParseTransferEventRejectsShortTopics(t *testing.T) {
+	_, err := ParseTransferEvent(Event{Topics: []string{ERC20_TRANSFER_SIGNATURE, "0x1", "0x2"}})
+	if err == nil {
+		t.Fatal("expected error for short topics")
+	}
+}
+
+func TestSynchronousHandlersRunInOrder(t *testing.T) {
+	monitor := NewEventMonitor().SetSynchronous(true)
+
+	var order []int
+	for i := 0; i < 3; i++ {
+		i := i
+		monitor.AddEventHandler(ERC20_TRANSFER_SIGNATURE, func(Event) error {
+			order = append(order, i)
+			if i == 1 {
+				panic("handler failure")
+			}
+			return nil
+		})
+	}
+
+	monitor.ProcessEvent(transferLog(testTokenAddress))
+
+	if len(order) != 3 || order[0] != 0 || order[1] != 1 || order[2] != 2 {
+		t.Fatalf("handler order = %v, want [0 1 2]", order)
+	}
+
+	select {
+	case err := <-monitor.Errors():
+		if !strings.Contains(err.Error(), "panicked") {
+			t.Errorf("error = %v, want handler panic", err)
+		}
+	default:
+		t.Fatal("expected panic to be reported on Errors")
+	}
+}
+
+func TestRestoreSubscription(t *testing.T) {
+	filter := NewEventFilter().AddAddress(testTokenAddress).SetEventSignature(ERC20_TRANSFER_SIGNATURE)
+	original := CreateDeterministicEventSubscription(filter)
+	persisted := original.Config()
+
+	monitor := NewEventMonitor()
+	restored := monitor.RestoreSubscription(original.ID, persisted)
+	if restored.ID != original.ID {
+		t.Errorf("restored id = %s, want %s", restored.ID, original.ID)
+	}
+	if DeterministicSubscriptionID(restored.Config()) != original.ID {
+		t.Error("restored filter does not match the original")
+	}
+
+	monitor.ProcessEvent(transferLog(testTokenAddress))
+	monitor.ProcessEvent(transferLog(testSpender))
+
+	select {
+	case event := <-restored.GetEvents():
+		if event.Address != testTokenAddress {
+			t.Errorf("event address = %s, want %s", event.Address, testTokenAddress)
+		}
+	default:
+		t.Fatal("restored subscription did not receive the matching event")
+	}
+	select {
+	case event := <-restored.GetEvents():
+		t.Errorf("restored subscription received non-matching event from %s", event.Address)
+	default:
+	}
+}
+
+func TestDeterministicSubscriptionID(t *testing.T) {
+	build := func(address string) *EventFilter {
+		return NewEventFilter().AddAddress(address).SetEventSignature(ERC20_TRANSFER_SIGNATURE)
+	}
+
+	first := DeterministicSubscriptionID(build(testTokenAddress))
+	second := DeterministicSubscriptionID(build(strings.ToLower(testTokenAddress)))
+	if first != second {
+		t.Errorf("identical filters produced %s and %s", first, second)
+	}
+	if CreateDeterministicEventSubscription(build(testTokenAddress)).ID != first {
+		t.Error("deterministic subscription does not use the filter id")
+	}
+
+	if other := DeterministicSubscriptionID(build(testSpender)); other == first {
+		t.Error("different filters produced the same id")
+	}
+	if CreateEventSubscription(build(testTokenAddress)).ID == first {
+		t.Error("default subscription id should not be deterministic")
+	}
+}
+
+func TestEventFilterSignatureAndIndexedAddressLayout(t *testing.T) {
+	filter := NewEventFilter().
+		SetEventSignature(ERC20_TRANSFER_SIGNATURE).
+		AddIndexedAddress(2, testSpender)
+	if err := filter.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+
+	want := [][]string{
+		{ERC20_TRANSFER_SIGNATURE},
+		{},
+		{testTopicTo},
+	}
+	if len(filter.Topics) != len(want) {
+		t.Fatalf("topics = %v, want %v", filter.Topics, want)
+	}
+	for i := range want {
+		if len(filter.Topics[i]) != len(want[i]) {
+			t.Fatalf("topic position %d = %v, want %v", i, filter.Topics[i], want[i])
+		}
+		for j := range want[i] {
+			if filter.Topics[i][j] != want[i][j] {
+				t.Errorf("topic [%d][%d] = %s, want %s", i, j, filter.Topics[i][j], want[i][j])
+			}
+		}
+	}
+}
+
+func TestSubscribeCheckedRejectsInvalidFilter(t *testing.T) {
+	monitor := NewEventMonitor()
+
+	filter := NewEventFilter().
+		SetEventSignature(ERC20_TRANSFER_SIGNATURE).
+		AddIndexedParameter(1, "0x1234")
+	if len(filter.Topics) != 1 {
+		t.Errorf("short topic was added to the filter: %v", filter.Topics)
+	}
+	if _, err := monitor.SubscribeChecked(filter); err == nil {
+		t.Fatal("expected SubscribeChecked to reject a filter with a short topic")
+	}
+	if _, err := monitor.SubscribeChecked(nil); err == nil {
+		t.Error("expected SubscribeChecked to reject a nil filter")
+	}
+	if len(monitor.subscriptions) != 0 {
+		t.Errorf("%d subscriptions registered, want 0", len(monitor.subscriptions))
+	}
+
+	valid := NewEventFilter().SetEventSignature(ERC20_TRANSFER_SIGNATURE).AddIndexedAddress(2, testSpender)
+	sub, err := monitor.SubscribeChecked(valid)
+	if err != nil {
+		t.Fatalf("SubscribeChecked failed: %v", err)
+	}
+	if monitor.subscriptions[sub.ID] != sub {
+		t.Error("checked subscription was not registered")
+	}
+}
+
+func TestMatchEventTransferTopic(t *testing.T) {
+	filter := NewEventFilter().MatchEvent("Transfer(address,address,uint256)")
+	if err := filter.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	want := "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"
+	if len(filter.Topics) != 1 || len(filter.Topics[0]) != 1 || filter.Topics[0][0] != want {
+		t.Errorf("topics = %v, want [[%s]]", filter.Topics, want)
+	}
+
+	for _, bad := range []string{"Transfer", "Transfer(address, address,uint256)", "(address)"} {
+		if err := NewEventFilter().MatchEvent(bad).Validate(); err == nil {
+			t.Errorf("MatchEvent(%q) should fail validation", bad)
+		}
+	}
+}
+
+func TestEncodeTopicAddress(t *testing.T) {
+	topic, err := EncodeTopicAddress(testOwner)
+	if err != nil {
+		t.Fatalf("EncodeTopicAddress failed: %v", err)
+	}
+	if topic != testTopicFrom {
+		t.Errorf("topic = %s, want %s", topic, testTopicFrom)
+	}
+	if _, err := EncodeTopicAddress("0x742d35Cc6634C0532925a3b844Bc454e4438f44"); err == nil {
+		t.Error("expected error for a 19-byte address")
+	}
+}
@@ -0,0 +1,167 @@
+package web3
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+)
+
+const (
+	BaseFeeChangeDenominator   = 8
+	ElasticityMultiplier       = 2
+	L1DataGasOverhead          = 188
+	ZeroByteDataGas            = 4
+	NonZeroByteDataGas         = 16
+	MinReplacementBumpPercent  = 10
+	MaxInclusionBlocks         = 100
+	InclusionTipBaseFeeDivisor = 10
+)
+
+type FeeData struct {
+	BaseFee        *big.Int
+	MaxPriorityFee *big.Int
+	MaxFee         *big.Int
+}
+
+func newFeeData(baseFee, maxPriorityFee *big.Int) *FeeData {
+	maxFee := new(big.Int).Mul(baseFee, big.NewInt(2))
+	maxFee.Add(maxFee, maxPriorityFee)
+
+	return &FeeData{
+		BaseFee:        baseFee,
+		MaxPriorityFee: maxPriorityFee,
+		MaxFee:         maxFee,
+	}
+}
+
+func PredictNextBaseFee(parentBaseFee *big.Int, parentGasUsed, parentGasLimit uint64) *big.Int {
+	gasTarget := parentGasLimit / ElasticityMultiplier
+	if gasTarget == 0 || parentGasUsed == gasTarget {
+		return new(big.Int).Set(parentBaseFee)
+	}
+
+	target := new(big.Int).SetUint64(gasTarget)
+
+	if parentGasUsed > gasTarget {
+		gasDelta := new(big.Int).SetUint64(parentGasUsed - gasTarget)
+		feeDelta := new(big.Int).Mul(parentBaseFee, gasDelta)
+		feeDelta.Div(feeDelta, target)
+		feeDelta.Div(feeDelta, big.NewInt(BaseFeeChangeDenominator))
+		if feeDelta.Sign() == 0 {
+			feeDelta.SetInt64(1)
+		}
+		return feeDelta.Add(parentBaseFee, feeDelta)
+	}
+
+	gasDelta := new(big.Int).SetUint64(gasTarget - parentGasUsed)
+	feeDelta := new(big.Int).Mul(parentBaseFee, gasDelta)
+	feeDelta.Div(feeDelta, target)
+	feeDelta.Div(feeDelta, big.NewInt(BaseFeeChangeDenominator))
+
+	nextBaseFee := new(big.Int).Sub(parentBaseFee, feeDelta)
+	if nextBaseFee.Sign() < 0 {
+		nextBaseFee.SetInt64(0)
+	}
+	return nextBaseFee
+}
+
+func EstimateL1DataGas(data []byte) uint64 {
+	return L1DataGasOverhead + calldataGas(data)
+}
+
+// calldataGas prices data at the EIP-2028 rates used both for L1 data
+// posting and for a transaction's own intrinsic gas.
+func calldataGas(data []byte) uint64 {
+	var gas uint64
+	for _, b := range data {
+		if b == 0 {
+			gas += ZeroByteDataGas
+		} else {
+			gas += NonZeroByteDataGas
+		}
+	}
+	return gas
+}
+
+func EstimateL1DataGasFromRawTx(rawTx string) (uint64, error) {
+	txBytes, err := normalizeHex(rawTx)
+	if err != nil {
+		return 0, fmt.Errorf("invalid raw transaction: %w", err)
+	}
+	return EstimateL1DataGas(txBytes), nil
+}
+
+// CompareGasCost returns nil costs when any of the prices is missing.
+func CompareGasCost(legacyGasPrice, maxFee, maxPriority, baseFee *big.Int, gas uint64) (legacyCost, dynamicCost *big.Int) {
+	if legacyGasPrice == nil || maxFee == nil || maxPriority == nil || baseFee == nil {
+		return nil, nil
+	}
+
+	legacyTx := &Transaction{Type: LegacyTxType, Gas: gas, GasPrice: legacyGasPrice}
+	dynamicTx := &Transaction{
+		Type:                 DynamicFeeTxType,
+		Gas:                  gas,
+		MaxFeePerGas:         maxFee,
+		MaxPriorityFeePerGas: maxPriority,
+	}
+
+	return legacyTx.CalculateFee(), dynamicTx.CalculateFeeWithBaseFee(baseFee)
+}
+
+func BumpFees(maxFee, maxPriority *big.Int, bumpPercent int) (newMaxFee, newMaxPriority *big.Int) {
+	if bumpPercent < MinReplacementBumpPercent {
+		bumpPercent = MinReplacementBumpPercent
+	}
+	return bumpFee(maxFee, bumpPercent), bumpFee(maxPriority, bumpPercent)
+}
+
+func bumpFee(fee *big.Int, bumpPercent int) *big.Int {
+	if fee == nil {
+		return nil
+	}
+
+	bumped := new(big.Int).Mul(fee, big.NewInt(int64(100+bumpPercent)))
+	bumped.Add(bumped, big.NewInt(99))
+	return bumped.Div(bumped, big.NewInt(100))
+}
+
+// EstimateInclusionBlocks compares the tip the transaction pays above the base
+// fee with the tip expected at priorityPercentile (0-100) of recent blocks.
+// Recent tips are modelled as spread evenly between zero and 1/
+// InclusionTipBaseFeeDivisor of the current base fee, so busier chains demand
+// proportionally larger tips. It returns -1 when the transaction is not
+// expected to be mined at all.
+func EstimateInclusionBlocks(txGasPrice, currentBaseFee *big.Int, priorityPercentile float64) (int, error) {
+	if txGasPrice == nil || currentBaseFee == nil {
+		return 0, fmt.Errorf("gas price and base fee are required")
+	}
+	if math.IsNaN(priorityPercentile) || priorityPercentile < 0 || priorityPercentile > 100 {
+		return 0, fmt.Errorf("priority percentile must be between 0 and 100, got %v", priorityPercentile)
+	}
+
+	tip := new(big.Int).Sub(txGasPrice, currentBaseFee)
+	if tip.Sign() < 0 {
+		return -1, nil
+	}
+
+	percentileTip := percentileTipFor(currentBaseFee, priorityPercentile)
+	if tip.Cmp(percentileTip) >= 0 {
+		return 1, nil
+	}
+	if tip.Sign() == 0 {
+		return -1, nil
+	}
+
+	blocks := new(big.Int).Add(percentileTip, new(big.Int).Sub(tip, big.NewInt(1)))
+	blocks.Div(blocks, tip)
+	if blocks.Cmp(big.NewInt(MaxInclusionBlocks)) > 0 {
+		return -1, nil
+	}
+	return int(blocks.Int64()), nil
+}
+
+func percentileTipFor(baseFee *big.Int, percentile float64) *big.Int {
+	basisPoints := big.NewInt(int64(math.Round(percentile * 100)))
+	tip := new(big.Int).Mul(baseFee, basisPoints)
+	return tip.Div(tip, big.NewInt(100*100*InclusionTipBaseFeeDivisor))
+}
@@ -0,0 +1,145 @@
+package web3
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestPredictNextBaseFee(t *testing.T) {
+	parent := big.NewInt(100_000_000_000)
+
+	tests := []struct {
+		name    string
+		gasUsed uint64
+		want    int64
+	}{
+		{"at target", 15_000_000, 100_000_000_000},
+		{"full block", 30_000_000, 112_500_000_000},
+		{"over target", 22_500_000, 106_250_000_000},
+		{"under target", 7_500_000, 93_750_000_000},
+		{"empty block", 0, 87_500_000_000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := PredictNextBaseFee(parent, tt.gasUsed, 30_000_000)
+			if got.Cmp(big.NewInt(tt.want)) != 0 {
+				t.Errorf("next base fee = %s, want %d", got, tt.want)
+			}
+		})
+	}
+
+	// The increase is at least 1 wei even when the formula rounds to zero.
+	if got := PredictNextBaseFee(big.NewInt(7), 15_000_001, 30_000_000); got.Int64() != 8 {
+		t.Errorf("minimum increase = %s, want 8", got)
+	}
+}
+
+func TestEstimateL1DataGas(t *testing.T) {
+	// 2 zero bytes and 3 non-zero bytes: 188 + 2*4 + 3*16.
+	payload := []byte{0x00, 0x01, 0x00, 0xff, 0xab}
+	if got := EstimateL1DataGas(payload); got != 244 {
+		t.Errorf("EstimateL1DataGas = %d, want 244", got)
+	}
+
+	got, err := EstimateL1DataGasFromRawTx("0x0001" + "00ffab")
+	if err != nil {
+		t.Fatalf("EstimateL1DataGasFromRawTx failed: %v", err)
+	}
+	if got != 244 {
+		t.Errorf("EstimateL1DataGasFromRawTx = %d, want 244", got)
+	}
+
+	if _, err := EstimateL1DataGasFromRawTx("0xzz"); err == nil {
+		t.Error("expected error for invalid raw transaction hex")
+	}
+}
+
+func TestCompareGasCost(t *testing.T) {
+	gwei := func(n int64) *big.Int { return new(big.Int).Mul(big.NewInt(n), big.NewInt(1e9)) }
+
+	legacyCost, dynamicCost := CompareGasCost(gwei(40), gwei(60), gwei(2), gwei(25), 21000)
+	if legacyCost.Cmp(new(big.Int).Mul(gwei(40), big.NewInt(21000))) != 0 {
+		t.Errorf("legacy cost = %s", legacyCost)
+	}
+	if dynamicCost.Cmp(new(big.Int).Mul(gwei(27), big.NewInt(21000))) != 0 {
+		t.Errorf("dynamic cost = %s", dynamicCost)
+	}
+	if dynamicCost.Cmp(legacyCost) >= 0 {
+		t.Errorf("dynamic cost %s should be lower than legacy cost %s", dynamicCost, legacyCost)
+	}
+
+	if legacyCost, dynamicCost := CompareGasCost(gwei(40), nil, gwei(2), gwei(25), 21000); legacyCost != nil || dynamicCost != nil {
+		t.Errorf("nil max fee gave costs %s and %s, want nil", legacyCost, dynamicCost)
+	}
+}
+
+func TestBumpFeesEnforcesReplacementFloor(t *testing.T) {
+	maxFee := big.NewInt(30_000_000_000)
+	maxPriority := big.NewInt(1_500_000_000)
+
+	newMaxFee, newMaxPriority := BumpFees(maxFee, maxPriority, 1)
+
+	for _, bump := range []struct {
+		name     string
+		old, new *big.Int
+	}{
+		{"max fee", maxFee, newMaxFee},
+		{"max priority fee", maxPriority, newMaxPriority},
+	} {
+		floor := new(big.Int).Mul(bump.old, big.NewInt(100+MinReplacementBumpPercent))
+		floor.Div(floor, big.NewInt(100))
+		if bump.new.Cmp(floor) < 0 {
+			t.Errorf("%s bumped to %s, want at least %s", bump.name, bump.new, floor)
+		}
+	}
+
+	// Rounding up keeps tiny fees from failing the 10% check.
+	_, tiny := BumpFees(big.NewInt(100), big.NewInt(1), 1)
+	if tiny.Int64() != 2 {
+		t.Errorf("1 wei priority fee bumped to %s, want 2", tiny)
+	}
+}
+
+func TestEstimateInclusionBlocks(t *testing.T) {
+	gwei := func(n int64) *big.Int { return new(big.Int).Mul(big.NewInt(n), big.NewInt(1e9)) }
+	// At the 50th percentile a 20 Gwei base fee expects a 1 Gwei tip.
+	baseFee := gwei(20)
+
+	tests := []struct {
+		name       string
+		gasPrice   *big.Int
+		percentile float64
+		want       int
+	}{
+		{"well priced", gwei(25), 50, 1},
+		{"exact percentile tip", gwei(21), 50, 1},
+		{"half the percentile tip", big.NewInt(20_500_000_000), 50, 2},
+		{"underpriced at a high percentile", big.NewInt(20_010_000_000), 90, -1},
+		{"below base fee", gwei(15), 50, -1},
+		{"no tip", gwei(20), 50, -1},
+		{"dust tip", new(big.Int).Add(baseFee, big.NewInt(1)), 50, -1},
+		{"any tip at the zeroth percentile", new(big.Int).Add(baseFee, big.NewInt(1)), 0, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := EstimateInclusionBlocks(tt.gasPrice, baseFee, tt.percentile)
+			if err != nil {
+				t.Fatalf("EstimateInclusionBlocks failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("EstimateInclusionBlocks = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	for _, percentile := range []float64{-1, 101} {
+		if _, err := EstimateInclusionBlocks(gwei(25), baseFee, percentile); err == nil {
+			t.Errorf("expected error for percentile %v", percentile)
+		}
+	}
+	if _, err := EstimateInclusionBlocks(gwei(25), nil, 50); err == nil {
+		t.Error("expected error for nil base fee")
+	}
+}
@@ -0,0 +1,22 @@
+package web3
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+func normalizeHex(s string) ([]byte, error) {
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		s = s[2:]
+	}
+	if len(s)%2 != 0 {
+		return nil, fmt.Errorf("odd-length hex string %q", s)
+	}
+
+	data, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid hex string: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,106 @@
+package web3
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestNormalizeHex(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []byte
+		wantErr bool
+	}{
+		{"prefixed", "0xdeadBEEF", []byte{0xde, 0xad, 0xbe, 0xef}, false},
+		{"upper prefix", "0Xdead", []byte{0xde, 0xad}, false},
+		{"bare", "deadbeef", []byte{0xde, 0xad, 0xbe, 0xef}, false},
+		{"empty", "", []byte{}, false},
+		{"prefix only", "0x", []byte{}, false},
+		{"odd length", "0xabc", nil, true},
+		{"non-hex", "0xzz", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeHex(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("normalizeHex(%q) = %x, want error", tt.input, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("normalizeHex(%q) failed: %v", tt.input, err)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("normalizeHex(%q) = %x, want %x", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEventDecodersNormalizeData(t *testing.T) {
+	for _, data := range []string{testAmount1M, testAmount1M[2:]} {
+		log := transferLog(testTokenAddress)
+		log.Data = data
+		event, err := ParseTransferEvent(log)
+		if err != nil {
+			t.Fatalf("ParseTransferEvent(%q) failed: %v", data, err)
+		}
+		if event.Amount.Int64() != 1_000_000 {
+			t.Errorf("amount = %s, want 1000000", event.Amount)
+		}
+
+		withdrawal, err := NewWETHToken(testWETHAddress).DecodeWithdrawalEvent(Event{
+			Topics: []string{WETH_WITHDRAWAL_SIGNATURE, testTopicTo},
+			Data:   data,
+		})
+		if err != nil {
+			t.Fatalf("DecodeWithdrawalEvent(%q) failed: %v", data, err)
+		}
+		if withdrawal.Amount.Int64() != 1_000_000 {
+			t.Errorf("withdrawal amount = %s, want 1000000", withdrawal.Amount)
+		}
+	}
+
+	log := transferLog(testTokenAddress)
+	log.Data = ""
+	if event, err := ParseTransferEvent(log); err != nil || event.Amount.Sign() != 0 {
+		t.Errorf("empty data = %v, %v, want zero amount", event, err)
+	}
+
+	log.Data = "0xabc"
+	if _, err := ParseTransferEvent(log); err == nil {
+		t.Error("expected error for odd-length transfer data")
+	}
+	if _, err := NewWETHToken(testWETHAddress).DecodeDepositEvent(Event{
+		Topics: []string{WETH_DEPOSIT_SIGNATURE, testTopicFrom},
+		Data:   "0xabc",
+	}); err == nil {
+		t.Error("expected error for odd-length deposit data")
+	}
+}
+
+func TestParseNFTTransferEvent(t *testing.T) {
+	log := transferLog(testTokenAddress)
+	log.Data = "0x"
+	log.Topics = append(log.Topics, "0x000000000000000000000000000000000000000000000000000000000000002a")
+
+	event, err := ParseNFTTransferEvent(log)
+	if err != nil {
+		t.Fatalf("ParseNFTTransferEvent failed: %v", err)
+	}
+	if event.From != testOwner || event.To != testSpender || event.TokenId.Int64() != 42 {
+		t.Errorf("event = %+v", event)
+	}
+
+	log.Topics[3] = "0x2a"
+	if _, err := ParseNFTTransferEvent(log); err == nil {
+		t.Error("expected error for short token id topic")
+	}
+	log.Topics[1] = "0x1234"
+	if _, err := ParseNFTTransferEvent(log); err == nil {
+		t.Error("expected error for short address topic")
+	}
+}
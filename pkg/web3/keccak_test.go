@@ -0,0 +1,40 @@
+package web3
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func TestKeccak256Vectors(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"", "c5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470"},
+		{"abc", "4e03657aea45a94fc7d47ba826c8d667c0d1e6e33a64a036ec44f58fa12d6c45"},
+		{"Transfer(address,address,uint256)", "ddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"},
+		{"Approval(address,address,uint256)", "8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925"},
+	}
+
+	for _, tt := range tests {
+		if got := Keccak256([]byte(tt.input)); got != tt.want {
+			t.Errorf("Keccak256(%q) = %s, want %s", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestKeccak256MultiPartAcrossRate(t *testing.T) {
+	// Inputs around the 136-byte rate exercise the padding and block paths.
+	for _, size := range []int{135, 136, 137, 272, 300} {
+		data := bytes.Repeat([]byte{0xa5}, size)
+		whole := keccak256(data)
+		split := keccak256(data[:size/3], data[size/3:])
+		if !bytes.Equal(whole, split) {
+			t.Errorf("size %d: split hash %x != whole hash %x", size, split, whole)
+		}
+		if hex.EncodeToString(whole) == Keccak256(data[:size-1]) {
+			t.Errorf("size %d: hash ignores the last byte", size)
+		}
+	}
+}
@@ -0,0 +1,245 @@
+package web3
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+const (
+	StandardScryptN = 1 << 18
+	StandardScryptP = 1
+	LightScryptN    = 1 << 12
+	LightScryptP    = 6
+
+	keystoreVersion = 3
+	scryptR         = 8
+	scryptDKLen     = 32
+
+	// Keystores are untrusted input, so KDF parameters are capped well above
+	// what geth writes (n=2^18, r=8 uses 256 MiB) to keep decryption bounded.
+	maxKeystoreScryptMemory = 1 << 30
+	maxKeystorePBKDF2Rounds = 1 << 24
+	maxKeystoreDKLen        = 1024
+)
+
+var ErrInvalidKeystorePassword = errors.New("could not decrypt keystore: MAC mismatch, wrong password")
+
+type keystoreJSON struct {
+	Address string         `json:"address"`
+	Crypto  keystoreCrypto `json:"crypto"`
+	ID      string         `json:"id"`
+	Version int            `json:"version"`
+}
+
+type keystoreCrypto struct {
+	Cipher       string                 `json:"cipher"`
+	CipherText   string                 `json:"ciphertext"`
+	CipherParams keystoreCipherParams   `json:"cipherparams"`
+	KDF          string                 `json:"kdf"`
+	KDFParams    map[string]interface{} `json:"kdfparams"`
+	MAC          string                 `json:"mac"`
+}
+
+type keystoreCipherParams struct {
+	IV string `json:"iv"`
+}
+
+func EncryptKeystore(privateKeyHex, password string) ([]byte, error) {
+	return encryptKeystore(privateKeyHex, password, StandardScryptN, StandardScryptP)
+}
+
+func encryptKeystore(privateKeyHex, password string, n, p int) ([]byte, error) {
+	d, err := parsePrivateKey(privateKeyHex)
+	if err != nil {
+		return nil, err
+	}
+	privateKey := d.FillBytes(make([]byte, 32))
+
+	address, err := PrivateKeyToAddress(privateKeyHex)
+	if err != nil {
+		return nil, err
+	}
+
+	salt := make([]byte, 32)
+	iv := make([]byte, aes.BlockSize)
+	id := make([]byte, 16)
+	for _, buf := range [][]byte{salt, iv, id} {
+		if _, err := rand.Read(buf); err != nil {
+			return nil, fmt.Errorf("failed to read random bytes: %w", err)
+		}
+	}
+
+	derivedKey, err := scryptKey([]byte(password), salt, n, scryptR, p, scryptDKLen)
+	if err != nil {
+		return nil, err
+	}
+
+	cipherText, err := aes128CTR(derivedKey[:16], iv, privateKey)
+	if err != nil {
+		return nil, err
+	}
+	mac := keccak256(derivedKey[16:32], cipherText)
+
+	return json.Marshal(keystoreJSON{
+		Address: strings.TrimPrefix(strings.ToLower(address), "0x"),
+		Crypto: keystoreCrypto{
+			Cipher:       "aes-128-ctr",
+			CipherText:   hex.EncodeToString(cipherText),
+			CipherParams: keystoreCipherParams{IV: hex.EncodeToString(iv)},
+			KDF:          "scrypt",
+			KDFParams: map[string]interface{}{
+				"n":     n,
+				"r":     scryptR,
+				"p":     p,
+				"dklen": scryptDKLen,
+				"salt":  hex.EncodeToString(salt),
+			},
+			MAC: hex.EncodeToString(mac),
+		},
+		ID:      formatUUID(id),
+		Version: keystoreVersion,
+	})
+}
+
+func DecryptKeystore(keyJSON []byte, password string) (string, error) {
+	var keystore keystoreJSON
+	if err := json.Unmarshal(keyJSON, &keystore); err != nil {
+		return "", fmt.Errorf("invalid keystore JSON: %w", err)
+	}
+	if keystore.Version != keystoreVersion {
+		return "", fmt.Errorf("unsupported keystore version %d", keystore.Version)
+	}
+	if keystore.Crypto.Cipher != "aes-128-ctr" {
+		return "", fmt.Errorf("unsupported keystore cipher %s", keystore.Crypto.Cipher)
+	}
+
+	cipherText, err := normalizeHex(keystore.Crypto.CipherText)
+	if err != nil {
+		return "", fmt.Errorf("invalid keystore ciphertext: %w", err)
+	}
+	iv, err := normalizeHex(keystore.Crypto.CipherParams.IV)
+	if err != nil {
+		return "", fmt.Errorf("invalid keystore iv: %w", err)
+	}
+	mac, err := normalizeHex(keystore.Crypto.MAC)
+	if err != nil {
+		return "", fmt.Errorf("invalid keystore mac: %w", err)
+	}
+
+	derivedKey, err := keystoreDerivedKey(keystore.Crypto, password)
+	if err != nil {
+		return "", err
+	}
+
+	if !hmac.Equal(keccak256(derivedKey[16:32], cipherText), mac) {
+		return "", ErrInvalidKeystorePassword
+	}
+
+	privateKey, err := aes128CTR(derivedKey[:16], iv, cipherText)
+	if err != nil {
+		return "", err
+	}
+
+	privateKeyHex := "0x" + hex.EncodeToString(privateKey)
+	if _, err := parsePrivateKey(privateKeyHex); err != nil {
+		return "", fmt.Errorf("keystore contains an invalid private key: %w", err)
+	}
+	return privateKeyHex, nil
+}
+
+// geth still reads pbkdf2 keystores written by older wallets, so both KDFs
+// from the Web3 Secret Storage definition are accepted here.
+func keystoreDerivedKey(params keystoreCrypto, password string) ([]byte, error) {
+	salt, err := normalizeHex(keystoreParamString(params.KDFParams, "salt"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid keystore salt: %w", err)
+	}
+	dkLen, err := keystoreParamInt(params.KDFParams, "dklen")
+	if err != nil {
+		return nil, err
+	}
+	if dkLen < 32 || dkLen > maxKeystoreDKLen {
+		return nil, fmt.Errorf("keystore dklen must be between 32 and %d, got %d", maxKeystoreDKLen, dkLen)
+	}
+
+	switch params.KDF {
+	case "scrypt":
+		n, err := keystoreParamInt(params.KDFParams, "n")
+		if err != nil {
+			return nil, err
+		}
+		r, err := keystoreParamInt(params.KDFParams, "r")
+		if err != nil {
+			return nil, err
+		}
+		p, err := keystoreParamInt(params.KDFParams, "p")
+		if err != nil {
+			return nil, err
+		}
+		if n&(n-1) != 0 || n < 2 {
+			return nil, fmt.Errorf("scrypt n must be a power of two greater than 1, got %d", n)
+		}
+		if uint64(r) > maxKeystoreScryptMemory/128 || 128*uint64(r)*(uint64(n)+uint64(p)) > maxKeystoreScryptMemory {
+			return nil, fmt.Errorf("scrypt parameters n=%d r=%d p=%d exceed the %d byte memory limit", n, r, p, maxKeystoreScryptMemory)
+		}
+		return scryptKey([]byte(password), salt, n, r, p, dkLen)
+	case "pbkdf2":
+		if prf := keystoreParamString(params.KDFParams, "prf"); prf != "hmac-sha256" {
+			return nil, fmt.Errorf("unsupported pbkdf2 prf %s", prf)
+		}
+		c, err := keystoreParamInt(params.KDFParams, "c")
+		if err != nil {
+			return nil, err
+		}
+		if c > maxKeystorePBKDF2Rounds {
+			return nil, fmt.Errorf("pbkdf2 iteration count %d exceeds %d", c, maxKeystorePBKDF2Rounds)
+		}
+		return pbkdf2SHA256([]byte(password), salt, c, dkLen), nil
+	default:
+		return nil, fmt.Errorf("unsupported keystore kdf %s", params.KDF)
+	}
+}
+
+// keystoreParamInt reads a JSON number, which encoding/json decodes as
+// float64, and rejects fractional, non-positive and oversized values.
+func keystoreParamInt(params map[string]interface{}, name string) (int, error) {
+	value, ok := params[name].(float64)
+	if !ok || value != math.Trunc(value) || value < 1 || value > math.MaxInt32 {
+		return 0, fmt.Errorf("keystore kdf parameter %s must be a positive integer, got %v", name, params[name])
+	}
+	return int(value), nil
+}
+
+func keystoreParamString(params map[string]interface{}, name string) string {
+	value, _ := params[name].(string)
+	return value
+}
+
+func aes128CTR(key, iv, input []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create aes cipher: %w", err)
+	}
+	if len(iv) != aes.BlockSize {
+		return nil, fmt.Errorf("keystore iv must be %d bytes, got %d", aes.BlockSize, len(iv))
+	}
+
+	output := make([]byte, len(input))
+	cipher.NewCTR(block, iv).XORKeyStream(output, input)
+	return output, nil
+}
+
+func formatUUID(id []byte) string {
+	id[6] = id[6]&0x0f | 0x40
+	id[8] = id[8]&0x3f | 0x80
+	h := hex.EncodeToString(id)
+	return h[:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:]
+}
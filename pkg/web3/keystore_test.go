@@ -0,0 +1,118 @@
+package web3
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// Test vectors from the Web3 Secret Storage definition; both decrypt to the
+// same private key with the password "testpassword".
+const (
+	web3StorageKey = "0x7a28b5ba57c53603b0b07b56bba752f7784bf506fa95edc395f5cf6c7514fe9d"
+
+	web3StorageScryptJSON = `{
+		"crypto": {
+			"cipher": "aes-128-ctr",
+			"cipherparams": {"iv": "83dbcc02d8ccb40e466191a123791e0e"},
+			"ciphertext": "d172bf743a674da9cdad04534d56926ef8358534d458fffccd4e6ad2fbde479c",
+			"kdf": "scrypt",
+			"kdfparams": {
+				"dklen": 32,
+				"n": 262144,
+				"p": 8,
+				"r": 1,
+				"salt": "ab0c7876052600dd703518d6fc3fe8984592145b591fc8fb5c6d43190334ba19"
+			},
+			"mac": "2103ac29920d71da29f15d75b4a16dbe95cfd7ff8faea1056c33131d846e3097"
+		},
+		"id": "3198bc9c-6672-5ab3-d995-4942343ae5b6",
+		"version": 3
+	}`
+
+	web3StoragePBKDF2JSON = `{
+		"crypto": {
+			"cipher": "aes-128-ctr",
+			"cipherparams": {"iv": "6087dab2f9fdbbfaddc31a909735c1e6"},
+			"ciphertext": "5318b4d5bcd28de64ee5559e671353e16f075ecae9f99c7a79a38af5f869aa46",
+			"kdf": "pbkdf2",
+			"kdfparams": {
+				"c": 262144,
+				"dklen": 32,
+				"prf": "hmac-sha256",
+				"salt": "ae3cd4e7013836a3df6bd7241b12db061dbe2c6785853cce422d148a624ce0bd"
+			},
+			"mac": "517ead924a9d0dc3124507e3393d175ce3ff7c1e96529c6c555ce9e51205e9b2"
+		},
+		"id": "3198bc9c-6672-5ab3-d995-4942343ae5b6",
+		"version": 3
+	}`
+)
+
+func TestDecryptKeystoreWeb3StorageVectors(t *testing.T) {
+	for name, keyJSON := range map[string]string{"scrypt": web3StorageScryptJSON, "pbkdf2": web3StoragePBKDF2JSON} {
+		key, err := DecryptKeystore([]byte(keyJSON), "testpassword")
+		if err != nil {
+			t.Fatalf("%s: DecryptKeystore failed: %v", name, err)
+		}
+		if key != web3StorageKey {
+			t.Errorf("%s: key = %s, want %s", name, key, web3StorageKey)
+		}
+	}
+
+	if _, err := DecryptKeystore([]byte(web3StoragePBKDF2JSON), "wrongpassword"); err != ErrInvalidKeystorePassword {
+		t.Errorf("error = %v, want ErrInvalidKeystorePassword", err)
+	}
+}
+
+func TestKeystoreRoundTrip(t *testing.T) {
+	keyJSON, err := encryptKeystore(eip155PrivateKey, "hunter2", LightScryptN, LightScryptP)
+	if err != nil {
+		t.Fatalf("encryptKeystore failed: %v", err)
+	}
+
+	var parsed keystoreJSON
+	if err := json.Unmarshal(keyJSON, &parsed); err != nil {
+		t.Fatal(err)
+	}
+	address, _ := PrivateKeyToAddress(eip155PrivateKey)
+	if parsed.Address != strings.ToLower(address[2:]) || parsed.Version != 3 {
+		t.Errorf("keystore address %s version %d", parsed.Address, parsed.Version)
+	}
+
+	key, err := DecryptKeystore(keyJSON, "hunter2")
+	if err != nil {
+		t.Fatalf("DecryptKeystore failed: %v", err)
+	}
+	if key != eip155PrivateKey {
+		t.Errorf("key = %s, want %s", key, eip155PrivateKey)
+	}
+	if _, err := DecryptKeystore(keyJSON, "hunter3"); err != ErrInvalidKeystorePassword {
+		t.Errorf("error = %v, want ErrInvalidKeystorePassword", err)
+	}
+}
+
+func TestDecryptKeystoreRejectsBadKDFParams(t *testing.T) {
+	tests := []struct {
+		name   string
+		params string
+	}{
+		{"negative dklen", `"kdf":"pbkdf2","kdfparams":{"c":1,"dklen":-1,"prf":"hmac-sha256","salt":"00"}`},
+		{"short dklen", `"kdf":"pbkdf2","kdfparams":{"c":1,"dklen":16,"prf":"hmac-sha256","salt":"00"}`},
+		{"missing dklen", `"kdf":"scrypt","kdfparams":{"n":2,"r":1,"p":1,"salt":"00"}`},
+		{"fractional n", `"kdf":"scrypt","kdfparams":{"dklen":32,"n":1024.5,"r":1,"p":1,"salt":"00"}`},
+		{"string r", `"kdf":"scrypt","kdfparams":{"dklen":32,"n":1024,"r":"8","p":1,"salt":"00"}`},
+		{"n not a power of two", `"kdf":"scrypt","kdfparams":{"dklen":32,"n":1000,"r":1,"p":1,"salt":"00"}`},
+		{"huge n", `"kdf":"scrypt","kdfparams":{"dklen":32,"n":1073741824,"r":8,"p":1,"salt":"00"}`},
+		{"huge p", `"kdf":"scrypt","kdfparams":{"dklen":32,"n":2,"r":8,"p":2000000000,"salt":"00"}`},
+		{"huge c", `"kdf":"pbkdf2","kdfparams":{"c":1e12,"dklen":32,"prf":"hmac-sha256","salt":"00"}`},
+	}
+
+	for _, tt := range tests {
+		keyJSON := `{"version":3,"crypto":{"cipher":"aes-128-ctr","cipherparams":{"iv":"00000000000000000000000000000000"},` +
+			`"ciphertext":"00","mac":"00",` + tt.params + `}}`
+		if _, err := DecryptKeystore([]byte(keyJSON), "x"); err == nil || err == ErrInvalidKeystorePassword {
+			t.Errorf("%s: error = %v, want a parameter error", tt.name, err)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package web3
+
+import (
+	"fmt"
+	"strconv"
+)
+
+const personalMessagePrefix = "\x19Ethereum Signed Message:\n"
+
+// The EIP-191 length is the decimal ASCII byte count ("12", not "c").
+func personalMessagePreimage(msg []byte) []byte {
+	preimage := []byte(personalMessagePrefix + strconv.Itoa(len(msg)))
+	return append(preimage, msg...)
+}
+
+func HashPersonalMessage(msg []byte) [32]byte {
+	var hash [32]byte
+	copy(hash[:], keccak256(personalMessagePreimage(msg)))
+	return hash
+}
+
+func SignMessage(msg []byte, privateKeyHex string) ([]byte, error) {
+	d, err := parsePrivateKey(privateKeyHex)
+	if err != nil {
+		return nil, err
+	}
+
+	r, s, recoveryID, err := signHashParity(HashPersonalMessage(msg), d)
+	if err != nil {
+		return nil, err
+	}
+
+	return signatureBytes(r, s, recoveryID+27), nil
+}
+
+func RecoverMessageSigner(msg, sig []byte) (string, error) {
+	address, err := ECRecover(HashPersonalMessage(msg), sig)
+	if err != nil {
+		return "", fmt.Errorf("failed to recover message signer: %w", err)
+	}
+	return address, nil
+}
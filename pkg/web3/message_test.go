@@ -0,0 +1,84 @@
+package web3
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestPersonalMessagePrefix(t *testing.T) {
+	msg := []byte("Hello World!")
+	want := append([]byte("\x19Ethereum Signed Message:\n12"), msg...)
+	if got := personalMessagePreimage(msg); !bytes.Equal(got, want) {
+		t.Fatalf("preimage = %q, want %q", got, want)
+	}
+
+	hash := HashPersonalMessage(msg)
+	if !bytes.Equal(hash[:], keccak256(want)) {
+		t.Errorf("hash = %x, want keccak256 of the preimage", hash)
+	}
+
+	// ethers.hashMessage("Hello World")
+	hash = HashPersonalMessage([]byte("Hello World"))
+	if got := hex.EncodeToString(hash[:]); got != "a1de988600a42c4b4ab089b619297c17d53cffae5d5120d82d8a92d0bb3b78f2" {
+		t.Errorf("hash of \"Hello World\" = %s", got)
+	}
+}
+
+func TestSignMessageRoundTrip256Bytes(t *testing.T) {
+	msg := bytes.Repeat([]byte{0xab}, 256)
+	if preimage := personalMessagePreimage(msg); !bytes.HasPrefix(preimage, []byte("\x19Ethereum Signed Message:\n256\xab")) {
+		t.Fatalf("preimage prefix = %q", preimage[:30])
+	}
+
+	sig, err := SignMessage(msg, eip155PrivateKey)
+	if err != nil {
+		t.Fatalf("SignMessage failed: %v", err)
+	}
+	if len(sig) != 65 || (sig[64] != 27 && sig[64] != 28) {
+		t.Fatalf("signature = %x, want 65 bytes with v of 27 or 28", sig)
+	}
+
+	signer, err := RecoverMessageSigner(msg, sig)
+	if err != nil {
+		t.Fatalf("RecoverMessageSigner failed: %v", err)
+	}
+	want, _ := PrivateKeyToAddress(eip155PrivateKey)
+	if !strings.EqualFold(signer, want) {
+		t.Errorf("signer = %s, want %s", signer, want)
+	}
+
+	if other, err := RecoverMessageSigner(msg[:255], sig); err == nil && strings.EqualFold(other, want) {
+		t.Error("signature should not verify a different message")
+	}
+}
+
+func TestRecoverMessageSignerWeb3JSVector(t *testing.T) {
+	// web3.eth.accounts.sign("Some data", "0x4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318")
+	msg := []byte("Some data")
+	hash := HashPersonalMessage(msg)
+	if got := hex.EncodeToString(hash[:]); got != "1da44b586eb0729ff70a73c326926f6ed5a25f5b056e7f47fbc6e58d86871655" {
+		t.Fatalf("message hash = %s", got)
+	}
+
+	sig, err := hex.DecodeString("b91467e570a6466aa9e9876cbcd013baba02900b8979d43fe208a4a4f339f5fd6007e74cd82e037b800186422fc2da167c747ef045e5d18a5f5d4300f8e1a0291c")
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := RecoverMessageSigner(msg, sig)
+	if err != nil {
+		t.Fatalf("RecoverMessageSigner failed: %v", err)
+	}
+	if !strings.EqualFold(signer, "0x2c7536E3605D9C16a7a3D7b1898e529396a65c23") {
+		t.Errorf("signer = %s, want 0x2c7536E3605D9C16a7a3D7b1898e529396a65c23", signer)
+	}
+
+	signed, err := SignMessage(msg, "0x4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318")
+	if err != nil {
+		t.Fatalf("SignMessage failed: %v", err)
+	}
+	if !bytes.Equal(signed, sig) {
+		t.Errorf("signature = %x, want %x", signed, sig)
+	}
+}
@@ -0,0 +1,91 @@
+package web3
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+)
+
+type CallResult struct {
+	Success    bool
+	ReturnData []byte
+}
+
+func DecodeMulticallWithBlock(data []byte) (*big.Int, string, []CallResult, error) {
+	if len(data) < 96 {
+		return nil, "", nil, fmt.Errorf("insufficient data for multicall block header")
+	}
+
+	blockNumber := new(big.Int).SetBytes(data[0:32])
+	blockHash := "0x" + hex.EncodeToString(data[32:64])
+
+	arrayOffset, err := readABIOffset(data, 64)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("invalid results offset: %w", err)
+	}
+
+	results, err := decodeCallResults(data, arrayOffset)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	return blockNumber, blockHash, results, nil
+}
+
+func decodeCallResults(data []byte, arrayOffset int) ([]CallResult, error) {
+	count, err := readABIOffset(data, arrayOffset)
+	if err != nil {
+		return nil, fmt.Errorf("invalid results length: %w", err)
+	}
+
+	base := arrayOffset + 32
+	if count > (len(data)-base)/32 {
+		return nil, fmt.Errorf("results length %d exceeds data", count)
+	}
+
+	results := make([]CallResult, count)
+	for i := range results {
+		tupleOffset, err := readABIOffset(data, base+i*32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid result %d offset: %w", i, err)
+		}
+		tupleStart := base + tupleOffset
+
+		success, _, err := decodeBool(data, tupleStart)
+		if err != nil {
+			return nil, fmt.Errorf("invalid result %d success flag: %w", i, err)
+		}
+
+		dataOffset, err := readABIOffset(data, tupleStart+32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid result %d data offset: %w", i, err)
+		}
+		dataStart := tupleStart + dataOffset
+
+		length, err := readABIOffset(data, dataStart)
+		if err != nil {
+			return nil, fmt.Errorf("invalid result %d data length: %w", i, err)
+		}
+		if length > len(data)-dataStart-32 {
+			return nil, fmt.Errorf("insufficient data for result %d return data", i)
+		}
+
+		returnData := make([]byte, length)
+		copy(returnData, data[dataStart+32:dataStart+32+length])
+		results[i] = CallResult{Success: success, ReturnData: returnData}
+	}
+
+	return results, nil
+}
+
+func readABIOffset(data []byte, offset int) (int, error) {
+	if offset < 0 || offset+32 > len(data) {
+		return 0, fmt.Errorf("insufficient data at offset %d", offset)
+	}
+
+	value := new(big.Int).SetBytes(data[offset : offset+32])
+	if !value.IsInt64() || value.Int64() > int64(len(data)) {
+		return 0, fmt.Errorf("value %s at offset %d is out of range", value.String(), offset)
+	}
+	return int(value.Int64()), nil
+}
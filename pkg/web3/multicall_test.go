@@ -0,0 +1,51 @@
+package web3
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestDecodeMulticallWithBlock(t *testing.T) {
+	// tryBlockAndAggregate return for a successful balanceOf and a reverted call.
+	data, _ := hex.DecodeString(strings.Join([]string{
+		"000000000000000000000000000000000000000000000000000000000012d687",
+		strings.Repeat("aa", 32),
+		"0000000000000000000000000000000000000000000000000000000000000060",
+		"0000000000000000000000000000000000000000000000000000000000000002",
+		"0000000000000000000000000000000000000000000000000000000000000040",
+		"00000000000000000000000000000000000000000000000000000000000000c0",
+		"0000000000000000000000000000000000000000000000000000000000000001",
+		"0000000000000000000000000000000000000000000000000000000000000040",
+		"0000000000000000000000000000000000000000000000000000000000000020",
+		"00000000000000000000000000000000000000000000000000000000000f4240",
+		"0000000000000000000000000000000000000000000000000000000000000000",
+		"0000000000000000000000000000000000000000000000000000000000000040",
+		"0000000000000000000000000000000000000000000000000000000000000004",
+		"08c379a000000000000000000000000000000000000000000000000000000000",
+	}, ""))
+
+	blockNumber, blockHash, results, err := DecodeMulticallWithBlock(data)
+	if err != nil {
+		t.Fatalf("DecodeMulticallWithBlock failed: %v", err)
+	}
+	if blockNumber.Int64() != 1234567 {
+		t.Errorf("block number = %s, want 1234567", blockNumber)
+	}
+	if blockHash != "0x"+strings.Repeat("aa", 32) {
+		t.Errorf("block hash = %s", blockHash)
+	}
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2", len(results))
+	}
+	if !results[0].Success || hex.EncodeToString(results[0].ReturnData) != testAmount1M[2:] {
+		t.Errorf("result 0 = %+v, want success with 1000000", results[0])
+	}
+	if results[1].Success || hex.EncodeToString(results[1].ReturnData) != "08c379a0" {
+		t.Errorf("result 1 = %+v, want failure with 08c379a0", results[1])
+	}
+
+	if _, _, _, err := DecodeMulticallWithBlock(data[:len(data)-64]); err == nil {
+		t.Error("expected error for truncated return data")
+	}
+}
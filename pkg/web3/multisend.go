@@ -0,0 +1,78 @@
+package web3
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+)
+
+const MULTISEND_SELECTOR = "8d80ff0a"
+
+const (
+	MultiSendCall         uint8 = 0
+	MultiSendDelegateCall uint8 = 1
+)
+
+type MultiSendTx struct {
+	Operation uint8
+	To        string
+	Value     *big.Int
+	Data      []byte
+}
+
+func EncodeMultiSend(txs []MultiSendTx) ([]byte, error) {
+	packed, err := PackMultiSend(txs)
+	if err != nil {
+		return nil, err
+	}
+
+	selector, _ := hex.DecodeString(MULTISEND_SELECTOR)
+	offset := make([]byte, 32)
+	offset[31] = 0x20
+
+	encoded, err := encodeBytes(packed)
+	if err != nil {
+		return nil, err
+	}
+
+	data := append(selector, offset...)
+	return append(data, encoded...), nil
+}
+
+// PackMultiSend uses the Safe MultiSend layout rather than the ABI: each
+// transaction is operation(1) ++ to(20) ++ value(32) ++ dataLength(32) ++ data.
+func PackMultiSend(txs []MultiSendTx) ([]byte, error) {
+	if len(txs) == 0 {
+		return nil, fmt.Errorf("at least one transaction is required")
+	}
+
+	var packed []byte
+	for i, tx := range txs {
+		if tx.Operation != MultiSendCall && tx.Operation != MultiSendDelegateCall {
+			return nil, fmt.Errorf("transaction %d has invalid operation %d", i, tx.Operation)
+		}
+		to, err := addressToBytes(tx.To)
+		if err != nil || len(to) != 20 {
+			return nil, fmt.Errorf("transaction %d has invalid to address: %s", i, tx.To)
+		}
+
+		value := make([]byte, 32)
+		if tx.Value != nil {
+			if tx.Value.Sign() < 0 || tx.Value.BitLen() > 256 {
+				return nil, fmt.Errorf("transaction %d value must fit in uint256", i)
+			}
+			tx.Value.FillBytes(value)
+		}
+
+		length := make([]byte, 32)
+		big.NewInt(int64(len(tx.Data))).FillBytes(length)
+
+		packed = append(packed, tx.Operation)
+		packed = append(packed, to...)
+		packed = append(packed, value...)
+		packed = append(packed, length...)
+		packed = append(packed, tx.Data...)
+	}
+
+	return packed, nil
+}
@@ -0,0 +1,88 @@
+package web3
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestPackMultiSendTwoTransactions(t *testing.T) {
+	transferData := mustDecodeHex(t, "a9059cbb"+
+		"000000000000000000000000"+strings.ToLower(testOwner[2:])+
+		"00000000000000000000000000000000000000000000000000000000000f4240")
+
+	txs := []MultiSendTx{
+		{Operation: MultiSendCall, To: testOwner, Value: big.NewInt(1_000_000_000_000_000_000)},
+		{Operation: MultiSendDelegateCall, To: testTokenAddress, Data: transferData},
+	}
+
+	packed, err := PackMultiSend(txs)
+	if err != nil {
+		t.Fatalf("PackMultiSend failed: %v", err)
+	}
+
+	// 1 + 20 + 32 + 32 = 85 header bytes per transaction, then the data.
+	if len(packed) != 85+85+len(transferData) {
+		t.Fatalf("packed length = %d, want %d", len(packed), 85+85+len(transferData))
+	}
+
+	first := packed[:85]
+	if first[0] != MultiSendCall {
+		t.Errorf("first operation = %d, want 0", first[0])
+	}
+	if !strings.EqualFold(hex.EncodeToString(first[1:21]), testOwner[2:]) {
+		t.Errorf("first to = %x, want %s", first[1:21], testOwner)
+	}
+	if new(big.Int).SetBytes(first[21:53]).String() != "1000000000000000000" {
+		t.Errorf("first value = %x", first[21:53])
+	}
+	if new(big.Int).SetBytes(first[53:85]).Sign() != 0 {
+		t.Errorf("first data length = %x, want 0", first[53:85])
+	}
+
+	second := packed[85:]
+	if second[0] != MultiSendDelegateCall {
+		t.Errorf("second operation = %d, want 1", second[0])
+	}
+	if !strings.EqualFold(hex.EncodeToString(second[1:21]), testTokenAddress[2:]) {
+		t.Errorf("second to = %x, want %s", second[1:21], testTokenAddress)
+	}
+	if new(big.Int).SetBytes(second[21:53]).Sign() != 0 {
+		t.Errorf("nil value should pack as zero, got %x", second[21:53])
+	}
+	if new(big.Int).SetBytes(second[53:85]).Int64() != int64(len(transferData)) {
+		t.Errorf("second data length = %x, want %d", second[53:85], len(transferData))
+	}
+	if !bytes.Equal(second[85:], transferData) {
+		t.Errorf("second data = %x, want %x", second[85:], transferData)
+	}
+
+	encoded, err := EncodeMultiSend(txs)
+	if err != nil {
+		t.Fatalf("EncodeMultiSend failed: %v", err)
+	}
+	if hex.EncodeToString(encoded[:4]) != MULTISEND_SELECTOR {
+		t.Errorf("selector = %x, want %s", encoded[:4], MULTISEND_SELECTOR)
+	}
+	if new(big.Int).SetBytes(encoded[36:68]).Int64() != int64(len(packed)) || !bytes.Equal(encoded[68:68+len(packed)], packed) {
+		t.Error("multiSend call data does not wrap the packed bytes")
+	}
+}
+
+func TestPackMultiSendRejectsInvalid(t *testing.T) {
+	tests := []MultiSendTx{
+		{Operation: 2, To: testOwner},
+		{Operation: MultiSendCall, To: "0x1234"},
+		{Operation: MultiSendCall, To: testOwner, Value: big.NewInt(-1)},
+	}
+	for _, tx := range tests {
+		if _, err := PackMultiSend([]MultiSendTx{tx}); err == nil {
+			t.Errorf("expected error for %+v", tx)
+		}
+	}
+	if _, err := PackMultiSend(nil); err == nil {
+		t.Error("expected error for an empty batch")
+	}
+}
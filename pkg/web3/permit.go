@@ -0,0 +1,194 @@
+package web3
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+const (
+	PERMIT_SELECTOR     = "d505accf"
+	DAI_PERMIT_SELECTOR = "8fcbaf0c"
+)
+
+var PERMIT_TYPEHASH = "0x" + Keccak256([]byte("Permit(address owner,address spender,uint256 value,uint256 nonce,uint256 deadline)"))
+
+var DAI_PERMIT_TYPEHASH = "0x" + Keccak256([]byte("Permit(address holder,address spender,uint256 nonce,uint256 expiry,bool allowed)"))
+
+var EIP712_DOMAIN_TYPEHASH = "0x" + Keccak256([]byte("EIP712Domain(string name,string version,uint256 chainId,address verifyingContract)"))
+
+// DomainSeparator hashes the four-field EIP-712 domain used by DAI and most
+// ERC-2612 tokens. It matches the token's DOMAIN_SEPARATOR() only when name,
+// version and chainID are exactly what the contract was deployed with.
+func DomainSeparator(name, version string, chainID *big.Int, verifyingContract string) ([32]byte, error) {
+	var separator [32]byte
+	if chainID == nil || chainID.Sign() <= 0 {
+		return separator, fmt.Errorf("chain ID must be positive")
+	}
+
+	params := []ABIParam{
+		{Name: "name", Type: "bytes32"},
+		{Name: "version", Type: "bytes32"},
+		{Name: "chainId", Type: "uint256"},
+		{Name: "verifyingContract", Type: "address"},
+	}
+	args, err := encodeParameters(params, []interface{}{keccak256([]byte(name)), keccak256([]byte(version)), chainID, verifyingContract})
+	if err != nil {
+		return separator, err
+	}
+
+	typeHash, _ := hex.DecodeString(EIP712_DOMAIN_TYPEHASH[2:])
+	copy(separator[:], keccak256(typeHash, args))
+	return separator, nil
+}
+
+func (token *ERC20Token) EncodePermit(owner, spender string, value, deadline *big.Int, v uint8, r, s [32]byte) ([]byte, error) {
+	if value == nil || deadline == nil {
+		return nil, fmt.Errorf("value and deadline are required")
+	}
+
+	selector, _ := hex.DecodeString(PERMIT_SELECTOR)
+
+	params := []ABIParam{
+		{Name: "owner", Type: "address"},
+		{Name: "spender", Type: "address"},
+		{Name: "value", Type: "uint256"},
+		{Name: "deadline", Type: "uint256"},
+		{Name: "v", Type: "uint8"},
+		{Name: "r", Type: "bytes32"},
+		{Name: "s", Type: "bytes32"},
+	}
+	args, err := encodeParameters(params, []interface{}{owner, spender, value, deadline, v, r[:], s[:]})
+	if err != nil {
+		return nil, err
+	}
+
+	return append(selector, args...), nil
+}
+
+func (token *ERC20Token) EncodePermitDigest(owner, spender string, value, nonce, deadline *big.Int, domainSeparator [32]byte) ([32]byte, error) {
+	var digest [32]byte
+	if value == nil || nonce == nil || deadline == nil {
+		return digest, fmt.Errorf("value, nonce and deadline are required")
+	}
+
+	params := []ABIParam{
+		{Name: "owner", Type: "address"},
+		{Name: "spender", Type: "address"},
+		{Name: "value", Type: "uint256"},
+		{Name: "nonce", Type: "uint256"},
+		{Name: "deadline", Type: "uint256"},
+	}
+	args, err := encodeParameters(params, []interface{}{owner, spender, value, nonce, deadline})
+	if err != nil {
+		return digest, err
+	}
+
+	typeHash, _ := hex.DecodeString(PERMIT_TYPEHASH[2:])
+	structHash := keccak256(typeHash, args)
+
+	return eip712Digest(domainSeparator, structHash), nil
+}
+
+// The token recovers the signer and compares it with owner, so signing with
+// any other key produces a permit that always reverts.
+func (token *ERC20Token) SignPermit(owner, spender string, value, nonce, deadline *big.Int, domainSeparator [32]byte, privateKeyHex string) (uint8, [32]byte, [32]byte, error) {
+	var r, s [32]byte
+
+	signer, err := PrivateKeyToAddress(privateKeyHex)
+	if err != nil {
+		return 0, r, s, err
+	}
+	if !strings.EqualFold(signer, owner) {
+		return 0, r, s, fmt.Errorf("private key belongs to %s, not owner %s", signer, owner)
+	}
+
+	digest, err := token.EncodePermitDigest(owner, spender, value, nonce, deadline, domainSeparator)
+	if err != nil {
+		return 0, r, s, err
+	}
+
+	d, err := parsePrivateKey(privateKeyHex)
+	if err != nil {
+		return 0, r, s, err
+	}
+	sigR, sigS, recoveryID, err := signHashParity(digest, d)
+	if err != nil {
+		return 0, r, s, err
+	}
+
+	sigR.FillBytes(r[:])
+	sigS.FillBytes(s[:])
+	return recoveryID + 27, r, s, nil
+}
+
+// DAI predates EIP-2612: its permit grants an all-or-nothing allowance via the
+// allowed flag and takes the holder's nonce and an expiry instead of a value
+// and deadline, so its selector and typehash differ from the standard permit.
+func (token *ERC20Token) EncodeDAIPermit(holder, spender string, nonce, expiry *big.Int, allowed bool, v uint8, r, s [32]byte) ([]byte, error) {
+	if !ValidateAddress(holder) {
+		return nil, fmt.Errorf("invalid holder address")
+	}
+	if !ValidateAddress(spender) {
+		return nil, fmt.Errorf("invalid spender address")
+	}
+
+	selector, _ := hex.DecodeString(DAI_PERMIT_SELECTOR)
+
+	args, err := encodeDAIPermitArgs(holder, spender, nonce, expiry, allowed)
+	if err != nil {
+		return nil, err
+	}
+
+	vBytes := make([]byte, 32)
+	vBytes[31] = v
+
+	data := append(selector, args...)
+	data = append(data, vBytes...)
+	data = append(data, r[:]...)
+	data = append(data, s[:]...)
+
+	return data, nil
+}
+
+func DAIPermitDigest(domainSeparator [32]byte, holder, spender string, nonce, expiry *big.Int, allowed bool) ([32]byte, error) {
+	var digest [32]byte
+	if !ValidateAddress(holder) {
+		return digest, fmt.Errorf("invalid holder address")
+	}
+	if !ValidateAddress(spender) {
+		return digest, fmt.Errorf("invalid spender address")
+	}
+
+	args, err := encodeDAIPermitArgs(holder, spender, nonce, expiry, allowed)
+	if err != nil {
+		return digest, err
+	}
+
+	typeHash, _ := hex.DecodeString(DAI_PERMIT_TYPEHASH[2:])
+	structHash := keccak256(typeHash, args)
+
+	return eip712Digest(domainSeparator, structHash), nil
+}
+
+func encodeDAIPermitArgs(holder, spender string, nonce, expiry *big.Int, allowed bool) ([]byte, error) {
+	if nonce == nil || expiry == nil {
+		return nil, fmt.Errorf("nonce and expiry are required")
+	}
+
+	params := []ABIParam{
+		{Name: "holder", Type: "address"},
+		{Name: "spender", Type: "address"},
+		{Name: "nonce", Type: "uint256"},
+		{Name: "expiry", Type: "uint256"},
+		{Name: "allowed", Type: "bool"},
+	}
+	return encodeParameters(params, []interface{}{holder, spender, nonce, expiry, allowed})
+}
+
+func eip712Digest(domainSeparator [32]byte, structHash []byte) [32]byte {
+	var digest [32]byte
+	copy(digest[:], keccak256([]byte{0x19, 0x01}, domainSeparator[:], structHash))
+	return digest
+}
@@ -0,0 +1,327 @@
+package web3
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+const (
+	EmptyTrieRoot = "0x56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421"
+	EmptyCodeHash = "0xc5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470"
+)
+
+type AccountProof struct {
+	Address      string
+	AccountProof []string
+	Balance      *big.Int
+	CodeHash     string
+	Nonce        uint64
+	StorageHash  string
+	StorageProof []StorageProof
+}
+
+type StorageProof struct {
+	Key   *big.Int
+	Value *big.Int
+	Proof []string
+}
+
+type rpcAccountProof struct {
+	Address      string            `json:"address"`
+	AccountProof []string          `json:"accountProof"`
+	Balance      string            `json:"balance"`
+	CodeHash     string            `json:"codeHash"`
+	Nonce        string            `json:"nonce"`
+	StorageHash  string            `json:"storageHash"`
+	StorageProof []rpcStorageProof `json:"storageProof"`
+}
+
+type rpcStorageProof struct {
+	Key   string   `json:"key"`
+	Value string   `json:"value"`
+	Proof []string `json:"proof"`
+}
+
+func (p *rpcAccountProof) toAccountProof() (*AccountProof, error) {
+	balance, err := decodeHexBig(p.Balance)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proof balance: %w", err)
+	}
+	nonce, err := decodeHexUint64(p.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proof nonce: %w", err)
+	}
+
+	proof := &AccountProof{
+		Address:      p.Address,
+		AccountProof: p.AccountProof,
+		Balance:      balance,
+		CodeHash:     p.CodeHash,
+		Nonce:        nonce,
+		StorageHash:  p.StorageHash,
+		StorageProof: make([]StorageProof, 0, len(p.StorageProof)),
+	}
+
+	for i, sp := range p.StorageProof {
+		key, err := decodeHexBig(sp.Key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid storage proof key at index %d: %w", i, err)
+		}
+		value, err := decodeHexBig(sp.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid storage proof value at index %d: %w", i, err)
+		}
+		proof.StorageProof = append(proof.StorageProof, StorageProof{
+			Key:   key,
+			Value: value,
+			Proof: sp.Proof,
+		})
+	}
+
+	return proof, nil
+}
+
+func VerifyAccountProof(stateRoot string, proof *AccountProof) error {
+	if proof == nil {
+		return fmt.Errorf("proof is required")
+	}
+	if proof.Balance == nil {
+		return fmt.Errorf("proof balance is required")
+	}
+
+	address, err := addressToBytes(proof.Address)
+	if err != nil || len(address) == 0 {
+		return fmt.Errorf("invalid proof address")
+	}
+
+	value, err := verifyProofStrings(stateRoot, keccak256(address), proof.AccountProof)
+	if err != nil {
+		return fmt.Errorf("invalid account proof: %w", err)
+	}
+
+	if value == nil {
+		if proof.Nonce != 0 || proof.Balance.Sign() != 0 ||
+			!strings.EqualFold(proof.StorageHash, EmptyTrieRoot) || !strings.EqualFold(proof.CodeHash, EmptyCodeHash) {
+			return fmt.Errorf("account is absent from state but proof reports non-empty fields")
+		}
+		return nil
+	}
+
+	decoded, err := DecodeRLP(value)
+	if err != nil {
+		return fmt.Errorf("invalid account encoding: %w", err)
+	}
+	fields, ok := decoded.([]interface{})
+	if !ok || len(fields) != 4 {
+		return fmt.Errorf("account must be an rlp list of 4 items")
+	}
+
+	nonce, err := rlpUint64(fields[0])
+	if err != nil {
+		return fmt.Errorf("invalid account nonce: %w", err)
+	}
+	balance, err := rlpBigInt(fields[1])
+	if err != nil {
+		return fmt.Errorf("invalid account balance: %w", err)
+	}
+	storageHash, ok := fields[2].([]byte)
+	if !ok {
+		return fmt.Errorf("invalid account storage hash")
+	}
+	codeHash, ok := fields[3].([]byte)
+	if !ok {
+		return fmt.Errorf("invalid account code hash")
+	}
+
+	if nonce != proof.Nonce {
+		return fmt.Errorf("nonce mismatch: proof has %d, state has %d", proof.Nonce, nonce)
+	}
+	if balance.Cmp(proof.Balance) != 0 {
+		return fmt.Errorf("balance mismatch: state has %s", balance.String())
+	}
+	if !strings.EqualFold(proof.StorageHash, "0x"+hex.EncodeToString(storageHash)) {
+		return fmt.Errorf("storage hash mismatch")
+	}
+	if !strings.EqualFold(proof.CodeHash, "0x"+hex.EncodeToString(codeHash)) {
+		return fmt.Errorf("code hash mismatch")
+	}
+
+	return nil
+}
+
+func VerifyStorageProof(storageHash string, proof StorageProof) error {
+	if proof.Key == nil || proof.Value == nil {
+		return fmt.Errorf("storage proof key and value are required")
+	}
+	if proof.Key.Sign() < 0 || proof.Key.BitLen() > 256 {
+		return fmt.Errorf("storage proof key must fit in 32 bytes")
+	}
+
+	slot := make([]byte, 32)
+	proof.Key.FillBytes(slot)
+
+	value, err := verifyProofStrings(storageHash, keccak256(slot), proof.Proof)
+	if err != nil {
+		return fmt.Errorf("invalid storage proof: %w", err)
+	}
+
+	stored := new(big.Int)
+	if value != nil {
+		decoded, err := DecodeRLP(value)
+		if err != nil {
+			return fmt.Errorf("invalid storage value encoding: %w", err)
+		}
+		stored, err = rlpBigInt(decoded)
+		if err != nil {
+			return fmt.Errorf("invalid storage value: %w", err)
+		}
+	}
+
+	if stored.Cmp(proof.Value) != 0 {
+		return fmt.Errorf("storage value mismatch: proof has %s, state has %s", proof.Value.String(), stored.String())
+	}
+	return nil
+}
+
+func verifyProofStrings(rootHex string, key []byte, proofHex []string) ([]byte, error) {
+	root, err := normalizeHex(rootHex)
+	if err != nil || len(root) != 32 {
+		return nil, fmt.Errorf("invalid root hash %s", rootHex)
+	}
+
+	proof := make([][]byte, len(proofHex))
+	for i, node := range proofHex {
+		proof[i], err = normalizeHex(node)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proof node %d: %w", i, err)
+		}
+	}
+
+	return verifyMerkleProof(root, key, proof)
+}
+
+func verifyMerkleProof(root, key []byte, proof [][]byte) ([]byte, error) {
+	if len(proof) == 0 {
+		if bytes.Equal(root, keccak256([]byte{0x80})) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("proof is empty")
+	}
+
+	path := keyToNibbles(key)
+	wantHash := root
+	proofIndex := 0
+	var node interface{}
+
+	// Nodes past the one that resolves the key were not needed to prove it,
+	// so a proof carrying them is malformed rather than merely verbose.
+	resolved := func(value []byte) ([]byte, error) {
+		if proofIndex != len(proof) {
+			return nil, fmt.Errorf("proof has %d unused trailing nodes", len(proof)-proofIndex)
+		}
+		return value, nil
+	}
+
+	for {
+		if node == nil {
+			if proofIndex >= len(proof) {
+				return nil, fmt.Errorf("proof is missing node %d", proofIndex)
+			}
+			raw := proof[proofIndex]
+			if !bytes.Equal(keccak256(raw), wantHash) {
+				return nil, fmt.Errorf("proof node %d does not match its hash", proofIndex)
+			}
+			proofIndex++
+
+			decoded, err := DecodeRLP(raw)
+			if err != nil {
+				return nil, fmt.Errorf("invalid proof node %d: %w", proofIndex-1, err)
+			}
+			node = decoded
+		}
+
+		items, ok := node.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("trie node must be an rlp list")
+		}
+
+		var child interface{}
+		switch len(items) {
+		case 17:
+			if len(path) == 0 {
+				value, ok := items[16].([]byte)
+				if !ok {
+					return nil, fmt.Errorf("invalid branch value")
+				}
+				if len(value) == 0 {
+					return resolved(nil)
+				}
+				return resolved(value)
+			}
+			child = items[path[0]]
+			path = path[1:]
+		case 2:
+			encodedPath, ok := items[0].([]byte)
+			if !ok || len(encodedPath) == 0 {
+				return nil, fmt.Errorf("invalid trie node path")
+			}
+			nibbles, isLeaf := decodeHexPrefix(encodedPath)
+			if isLeaf {
+				if !bytes.Equal(nibbles, path) {
+					return resolved(nil)
+				}
+				value, ok := items[1].([]byte)
+				if !ok {
+					return nil, fmt.Errorf("invalid leaf value")
+				}
+				return resolved(value)
+			}
+			if len(path) < len(nibbles) || !bytes.Equal(nibbles, path[:len(nibbles)]) {
+				return resolved(nil)
+			}
+			path = path[len(nibbles):]
+			child = items[1]
+		default:
+			return nil, fmt.Errorf("trie node has %d items", len(items))
+		}
+
+		switch ref := child.(type) {
+		case []interface{}:
+			node = ref
+		case []byte:
+			if len(ref) == 0 {
+				return resolved(nil)
+			}
+			if len(ref) != 32 {
+				return nil, fmt.Errorf("invalid child reference of %d bytes", len(ref))
+			}
+			wantHash = ref
+			node = nil
+		default:
+			return nil, fmt.Errorf("invalid child reference")
+		}
+	}
+}
+
+func keyToNibbles(key []byte) []byte {
+	nibbles := make([]byte, len(key)*2)
+	for i, b := range key {
+		nibbles[i*2] = b >> 4
+		nibbles[i*2+1] = b & 0x0f
+	}
+	return nibbles
+}
+
+func decodeHexPrefix(encoded []byte) ([]byte, bool) {
+	flag := encoded[0] >> 4
+	isLeaf := flag&2 != 0
+
+	nibbles := keyToNibbles(encoded)
+	if flag&1 != 0 {
+		return nibbles[1:], isLeaf
+	}
+	return nibbles[2:], isLeaf
+}
@@ -0,0 +1,138 @@
+package web3
+
+import (
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+// testLeaf builds a trie leaf node for the remaining key nibbles using the
+// hex-prefix encoding from the Yellow Paper.
+func testLeaf(t *testing.T, nibbles []byte, value []byte) []byte {
+	t.Helper()
+	var path []byte
+	if len(nibbles)%2 == 1 {
+		path = append(path, 0x30|nibbles[0])
+		nibbles = nibbles[1:]
+	} else {
+		path = append(path, 0x20)
+	}
+	for i := 0; i < len(nibbles); i += 2 {
+		path = append(path, nibbles[i]<<4|nibbles[i+1])
+	}
+
+	node, err := EncodeRLP([]interface{}{path, value})
+	if err != nil {
+		t.Fatalf("EncodeRLP failed: %v", err)
+	}
+	return node
+}
+
+func testAccount(t *testing.T, nonce uint64, balance *big.Int) []byte {
+	t.Helper()
+	storageRoot, _ := hex.DecodeString(EmptyTrieRoot[2:])
+	codeHash, _ := hex.DecodeString(EmptyCodeHash[2:])
+	account, err := EncodeRLP([]interface{}{nonce, balance, storageRoot, codeHash})
+	if err != nil {
+		t.Fatalf("EncodeRLP failed: %v", err)
+	}
+	return account
+}
+
+// testStateProof builds a two-account state trie whose root is a branch node
+// and returns the state root with the proof for the first account.
+func testStateProof(t *testing.T, first, second string, balance *big.Int) (string, []string) {
+	t.Helper()
+	firstBytes, _ := addressToBytes(first)
+	secondBytes, _ := addressToBytes(second)
+	firstPath := keyToNibbles(keccak256(firstBytes))
+	secondPath := keyToNibbles(keccak256(secondBytes))
+	if firstPath[0] == secondPath[0] {
+		t.Fatal("test accounts share a first nibble and need an extension node")
+	}
+
+	firstLeaf := testLeaf(t, firstPath[1:], testAccount(t, 5, balance))
+	secondLeaf := testLeaf(t, secondPath[1:], testAccount(t, 0, big.NewInt(1)))
+
+	branch := make([]interface{}, 17)
+	for i := range branch {
+		branch[i] = []byte{}
+	}
+	branch[firstPath[0]] = keccak256(firstLeaf)
+	branch[secondPath[0]] = keccak256(secondLeaf)
+	root, err := EncodeRLP(branch)
+	if err != nil {
+		t.Fatalf("EncodeRLP failed: %v", err)
+	}
+
+	return "0x" + hex.EncodeToString(keccak256(root)), []string{
+		"0x" + hex.EncodeToString(root),
+		"0x" + hex.EncodeToString(firstLeaf),
+	}
+}
+
+func TestVerifyAccountProof(t *testing.T) {
+	balance := mustBig(t, "1500000000000000000")
+	stateRoot, nodes := testStateProof(t, testOwner, testSpender, balance)
+
+	proof := &AccountProof{
+		Address:      testOwner,
+		AccountProof: nodes,
+		Balance:      balance,
+		CodeHash:     EmptyCodeHash,
+		Nonce:        5,
+		StorageHash:  EmptyTrieRoot,
+	}
+	if err := VerifyAccountProof(stateRoot, proof); err != nil {
+		t.Fatalf("VerifyAccountProof failed: %v", err)
+	}
+
+	tampered := *proof
+	tampered.Balance = big.NewInt(1)
+	if err := VerifyAccountProof(stateRoot, &tampered); err == nil || !strings.Contains(err.Error(), "balance mismatch") {
+		t.Errorf("error = %v, want balance mismatch", err)
+	}
+
+	tampered = *proof
+	tampered.Balance = nil
+	if err := VerifyAccountProof(stateRoot, &tampered); err == nil {
+		t.Error("expected error for nil balance")
+	}
+
+	tampered = *proof
+	tampered.AccountProof = append(append([]string{}, nodes...), nodes[1])
+	if err := VerifyAccountProof(stateRoot, &tampered); err == nil || !strings.Contains(err.Error(), "trailing") {
+		t.Errorf("error = %v, want trailing node rejection", err)
+	}
+
+	tampered = *proof
+	tampered.AccountProof = nodes[:1]
+	if err := VerifyAccountProof(stateRoot, &tampered); err == nil {
+		t.Error("expected error for truncated proof")
+	}
+}
+
+func TestVerifyAccountProofEmptyState(t *testing.T) {
+	proof := &AccountProof{
+		Address:     testOwner,
+		Balance:     new(big.Int),
+		CodeHash:    EmptyCodeHash,
+		StorageHash: EmptyTrieRoot,
+	}
+	if err := VerifyAccountProof(EmptyTrieRoot, proof); err != nil {
+		t.Fatalf("absent account in empty state rejected: %v", err)
+	}
+
+	proof.Balance = big.NewInt(1)
+	if err := VerifyAccountProof(EmptyTrieRoot, proof); err == nil {
+		t.Error("expected error for absent account with a balance")
+	}
+}
+
+func TestVerifyStorageProofRejectsWideKey(t *testing.T) {
+	proof := StorageProof{Key: new(big.Int).Lsh(big.NewInt(1), 256), Value: new(big.Int)}
+	if err := VerifyStorageProof(EmptyTrieRoot, proof); err == nil {
+		t.Fatal("expected error for key wider than 32 bytes")
+	}
+}
@@ -0,0 +1,128 @@
+package web3
+
+import "fmt"
+
+type rpcReceipt struct {
+	TransactionHash   string   `json:"transactionHash"`
+	BlockNumber       string   `json:"blockNumber"`
+	BlockHash         string   `json:"blockHash"`
+	TransactionIndex  string   `json:"transactionIndex"`
+	From              string   `json:"from"`
+	To                *string  `json:"to"`
+	GasUsed           string   `json:"gasUsed"`
+	Status            *string  `json:"status"`
+	ContractAddress   *string  `json:"contractAddress"`
+	Logs              []rpcLog `json:"logs"`
+	CumulativeGasUsed string   `json:"cumulativeGasUsed"`
+}
+
+type rpcLog struct {
+	Address          string   `json:"address"`
+	Topics           []string `json:"topics"`
+	Data             string   `json:"data"`
+	BlockNumber      string   `json:"blockNumber"`
+	BlockHash        string   `json:"blockHash"`
+	TransactionHash  string   `json:"transactionHash"`
+	TransactionIndex string   `json:"transactionIndex"`
+	LogIndex         string   `json:"logIndex"`
+	Removed          bool     `json:"removed"`
+}
+
+func (r *rpcReceipt) toReceipt() (*TransactionReceipt, error) {
+	blockNumber, err := decodeHexBig(r.BlockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("invalid receipt block number: %w", err)
+	}
+	txIndex, err := decodeHexUint64(r.TransactionIndex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid receipt transaction index: %w", err)
+	}
+	gasUsed, err := decodeHexUint64(r.GasUsed)
+	if err != nil {
+		return nil, fmt.Errorf("invalid receipt gas used: %w", err)
+	}
+	cumulativeGasUsed, err := decodeHexUint64(r.CumulativeGasUsed)
+	if err != nil {
+		return nil, fmt.Errorf("invalid receipt cumulative gas used: %w", err)
+	}
+
+	receipt := &TransactionReceipt{
+		Hash:              r.TransactionHash,
+		BlockNumber:       blockNumber,
+		BlockHash:         r.BlockHash,
+		TransactionIndex:  uint(txIndex),
+		From:              r.From,
+		GasUsed:           gasUsed,
+		CumulativeGasUsed: cumulativeGasUsed,
+		Logs:              make([]Log, 0, len(r.Logs)),
+	}
+
+	if r.To != nil {
+		receipt.To = *r.To
+	}
+	if r.ContractAddress != nil {
+		receipt.ContractAddress = *r.ContractAddress
+	}
+	if r.Status != nil {
+		receipt.Status, err = decodeHexUint64(*r.Status)
+		if err != nil {
+			return nil, fmt.Errorf("invalid receipt status: %w", err)
+		}
+		receipt.HasStatus = true
+	}
+
+	for i := range r.Logs {
+		log, err := r.Logs[i].toLog()
+		if err != nil {
+			return nil, fmt.Errorf("invalid receipt log %d: %w", i, err)
+		}
+		receipt.Logs = append(receipt.Logs, *log)
+	}
+
+	return receipt, nil
+}
+
+func (l *rpcLog) toLog() (*Log, error) {
+	blockNumber, err := decodeHexBig(l.BlockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("invalid log block number: %w", err)
+	}
+	txIndex, err := decodeHexUint64(l.TransactionIndex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid log transaction index: %w", err)
+	}
+	logIndex, err := decodeHexUint64(l.LogIndex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid log index: %w", err)
+	}
+
+	return &Log{
+		Address:     l.Address,
+		Topics:      l.Topics,
+		Data:        l.Data,
+		BlockNumber: blockNumber,
+		BlockHash:   l.BlockHash,
+		TxHash:      l.TransactionHash,
+		TxIndex:     uint(txIndex),
+		LogIndex:    uint(logIndex),
+	}, nil
+}
+
+func (l *rpcLog) toEvent() (*Event, error) {
+	log, err := l.toLog()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Event{
+		Address:          log.Address,
+		Topics:           log.Topics,
+		Data:             log.Data,
+		BlockNumber:      log.BlockNumber,
+		TransactionHash:  log.TxHash,
+		TransactionIndex: log.TxIndex,
+		BlockHash:        log.BlockHash,
+		LogIndex:         log.LogIndex,
+		Removed:          l.Removed,
+	}, nil
+}
@@ -0,0 +1,148 @@
+package web3
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testTxHash = "0x" + strings.Repeat("5c", 32)
+
+func stubReceipt(status string) map[string]interface{} {
+	return map[string]interface{}{
+		"transactionHash":   testTxHash,
+		"blockNumber":       "0x12d687",
+		"blockHash":         "0x" + strings.Repeat("ab", 32),
+		"transactionIndex":  "0x3",
+		"from":              strings.ToLower(testOwner),
+		"to":                strings.ToLower(testTokenAddress),
+		"gasUsed":           "0xb411",
+		"cumulativeGasUsed": "0x2dc6c0",
+		"status":            status,
+		"contractAddress":   nil,
+		"logs": []map[string]interface{}{{
+			"address":          strings.ToLower(testTokenAddress),
+			"topics":           []string{ERC20_TRANSFER_SIGNATURE, testTopicFrom, testTopicTo},
+			"data":             testAmount1M,
+			"blockNumber":      "0x12d687",
+			"blockHash":        "0x" + strings.Repeat("ab", 32),
+			"transactionHash":  testTxHash,
+			"transactionIndex": "0x3",
+			"logIndex":         "0x7",
+			"removed":          false,
+		}},
+	}
+}
+
+func TestGetTransactionReceipt(t *testing.T) {
+	client, _ := newFakeClient(func(method string, params []json.RawMessage) (interface{}, *RPCError) {
+		if method != "eth_getTransactionReceipt" {
+			t.Fatalf("unexpected method %s", method)
+		}
+		return stubReceipt("0x1"), nil
+	})
+
+	receipt, err := client.GetTransactionReceipt(testTxHash)
+	if err != nil {
+		t.Fatalf("GetTransactionReceipt failed: %v", err)
+	}
+	if !receipt.HasStatus || receipt.Status != 1 || receipt.GasUsed != 0xb411 || receipt.BlockNumber.Int64() != 0x12d687 || receipt.TransactionIndex != 3 {
+		t.Errorf("receipt = %+v", receipt)
+	}
+	if receipt.ContractAddress != "" {
+		t.Errorf("contract address = %q, want empty", receipt.ContractAddress)
+	}
+	if len(receipt.Logs) != 1 {
+		t.Fatalf("got %d logs, want 1", len(receipt.Logs))
+	}
+
+	log := receipt.Logs[0]
+	if log.LogIndex != 7 || log.TxHash != testTxHash || log.Data != testAmount1M || len(log.Topics) != 3 {
+		t.Errorf("log = %+v", log)
+	}
+}
+
+func TestGetTransactionReceiptWithoutStatus(t *testing.T) {
+	client, _ := newFakeClient(func(method string, params []json.RawMessage) (interface{}, *RPCError) {
+		receipt := stubReceipt("")
+		delete(receipt, "status")
+		receipt["root"] = "0x" + strings.Repeat("cd", 32)
+		return receipt, nil
+	})
+
+	receipt, err := client.GetTransactionReceipt(testTxHash)
+	if err != nil {
+		t.Fatalf("GetTransactionReceipt failed: %v", err)
+	}
+	if receipt.HasStatus {
+		t.Errorf("receipt without status reported status %d", receipt.Status)
+	}
+
+	// An unknown status is not a revert.
+	if _, err := client.WaitForReceipt(context.Background(), testTxHash, time.Millisecond); err != nil {
+		t.Errorf("WaitForReceipt without status = %v, want no error", err)
+	}
+}
+
+func TestGetTransactionReceiptPending(t *testing.T) {
+	client, _ := newFakeClient(func(method string, params []json.RawMessage) (interface{}, *RPCError) {
+		return nil, nil
+	})
+
+	receipt, err := client.GetTransactionReceipt(testTxHash)
+	if err != nil || receipt != nil {
+		t.Fatalf("pending receipt = %v, %v, want nil, nil", receipt, err)
+	}
+}
+
+func TestWaitForReceipt(t *testing.T) {
+	calls := 0
+	client, _ := newFakeClient(func(method string, params []json.RawMessage) (interface{}, *RPCError) {
+		calls++
+		if calls <= 2 {
+			return nil, nil
+		}
+		return stubReceipt("0x1"), nil
+	})
+
+	receipt, err := client.WaitForReceipt(context.Background(), testTxHash, time.Millisecond)
+	if err != nil {
+		t.Fatalf("WaitForReceipt failed: %v", err)
+	}
+	if receipt == nil || receipt.Status != 1 {
+		t.Fatalf("receipt = %+v, want successful receipt", receipt)
+	}
+	if calls != 3 {
+		t.Errorf("polled %d times, want 3", calls)
+	}
+}
+
+func TestWaitForReceiptErrors(t *testing.T) {
+	reverted, _ := newFakeClient(func(method string, params []json.RawMessage) (interface{}, *RPCError) {
+		return stubReceipt("0x0"), nil
+	})
+	if _, err := reverted.WaitForReceipt(context.Background(), testTxHash, time.Millisecond); !errors.Is(err, ErrTransactionReverted) {
+		t.Errorf("reverted error = %v, want ErrTransactionReverted", err)
+	}
+
+	pending, _ := newFakeClient(func(method string, params []json.RawMessage) (interface{}, *RPCError) {
+		return nil, nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := pending.WaitForReceipt(ctx, testTxHash, time.Millisecond)
+	if !errors.Is(err, ErrReceiptTimeout) {
+		t.Errorf("deadline error = %v, want ErrReceiptTimeout", err)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	_, err = pending.WaitForReceipt(ctx, testTxHash, time.Millisecond)
+	if !errors.Is(err, context.Canceled) || errors.Is(err, ErrReceiptTimeout) {
+		t.Errorf("cancelled error = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,150 @@
+package web3
+
+import (
+	"fmt"
+	"math/big"
+)
+
+func EncodeRLP(items ...interface{}) ([]byte, error) {
+	var encoded []byte
+	for _, item := range items {
+		itemEncoded, err := encodeRLPItem(item)
+		if err != nil {
+			return nil, err
+		}
+		encoded = append(encoded, itemEncoded...)
+	}
+	return encoded, nil
+}
+
+func encodeRLPItem(item interface{}) ([]byte, error) {
+	switch v := item.(type) {
+	case []byte:
+		return encodeRLPString(v), nil
+	case string:
+		return encodeRLPString([]byte(v)), nil
+	case *big.Int:
+		if v == nil {
+			return encodeRLPString(nil), nil
+		}
+		if v.Sign() < 0 {
+			return nil, fmt.Errorf("rlp cannot encode negative integer %s", v.String())
+		}
+		return encodeRLPString(v.Bytes()), nil
+	case uint64:
+		return encodeRLPString(new(big.Int).SetUint64(v).Bytes()), nil
+	case uint:
+		return encodeRLPString(new(big.Int).SetUint64(uint64(v)).Bytes()), nil
+	case int:
+		if v < 0 {
+			return nil, fmt.Errorf("rlp cannot encode negative integer %d", v)
+		}
+		return encodeRLPString(big.NewInt(int64(v)).Bytes()), nil
+	case []interface{}:
+		payload, err := EncodeRLP(v...)
+		if err != nil {
+			return nil, err
+		}
+		return append(encodeRLPLength(len(payload), 0xc0), payload...), nil
+	default:
+		return nil, fmt.Errorf("rlp cannot encode type %T", item)
+	}
+}
+
+func encodeRLPString(data []byte) []byte {
+	if len(data) == 1 && data[0] < 0x80 {
+		return []byte{data[0]}
+	}
+	return append(encodeRLPLength(len(data), 0x80), data...)
+}
+
+func encodeRLPLength(length int, offset byte) []byte {
+	if length <= 55 {
+		return []byte{offset + byte(length)}
+	}
+
+	lengthBytes := big.NewInt(int64(length)).Bytes()
+	return append([]byte{offset + 55 + byte(len(lengthBytes))}, lengthBytes...)
+}
+
+func DecodeRLP(data []byte) (interface{}, error) {
+	item, rest, err := decodeRLPItem(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		return nil, fmt.Errorf("rlp has %d trailing bytes", len(rest))
+	}
+	return item, nil
+}
+
+func decodeRLPItem(data []byte) (interface{}, []byte, error) {
+	if len(data) == 0 {
+		return nil, nil, fmt.Errorf("rlp input is empty")
+	}
+
+	prefix := data[0]
+	switch {
+	case prefix < 0x80:
+		return []byte{prefix}, data[1:], nil
+
+	case prefix <= 0xbf:
+		offset, length, err := decodeRLPLength(data, 0x80)
+		if err != nil {
+			return nil, nil, err
+		}
+		content := data[offset : offset+length]
+		if length == 1 && content[0] < 0x80 {
+			return nil, nil, fmt.Errorf("non-canonical rlp single byte string")
+		}
+		return content, data[offset+length:], nil
+
+	default:
+		offset, length, err := decodeRLPLength(data, 0xc0)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		payload := data[offset : offset+length]
+		items := make([]interface{}, 0)
+		for len(payload) > 0 {
+			var item interface{}
+			item, payload, err = decodeRLPItem(payload)
+			if err != nil {
+				return nil, nil, err
+			}
+			items = append(items, item)
+		}
+		return items, data[offset+length:], nil
+	}
+}
+
+func decodeRLPLength(data []byte, offset byte) (int, int, error) {
+	prefix := data[0] - offset
+
+	if prefix <= 55 {
+		length := int(prefix)
+		if 1+length > len(data) {
+			return 0, 0, fmt.Errorf("rlp value exceeds input length")
+		}
+		return 1, length, nil
+	}
+
+	lengthOfLength := int(prefix - 55)
+	if 1+lengthOfLength > len(data) {
+		return 0, 0, fmt.Errorf("rlp length exceeds input length")
+	}
+	if data[1] == 0 {
+		return 0, 0, fmt.Errorf("non-canonical rlp length with leading zeros")
+	}
+
+	length := new(big.Int).SetBytes(data[1 : 1+lengthOfLength])
+	if !length.IsInt64() || length.Int64() > int64(len(data)-1-lengthOfLength) {
+		return 0, 0, fmt.Errorf("rlp value exceeds input length")
+	}
+	if length.Int64() <= 55 {
+		return 0, 0, fmt.Errorf("non-canonical rlp long form for short value")
+	}
+
+	return 1 + lengthOfLength, int(length.Int64()), nil
+}
@@ -0,0 +1,81 @@
+package web3
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestEncodeRLP(t *testing.T) {
+	tests := []struct {
+		name string
+		item interface{}
+		want string
+	}{
+		{"empty string", "", "80"},
+		{"empty list", []interface{}{}, "c0"},
+		{"dog", "dog", "83646f67"},
+		{"cat dog list", []interface{}{"cat", "dog"}, "c88363617483646f67"},
+		{"zero", big.NewInt(0), "80"},
+		{"single low byte", []byte{0x0f}, "0f"},
+		{"1024", uint64(1024), "820400"},
+		{"set of three", []interface{}{[]interface{}{}, []interface{}{[]interface{}{}}, []interface{}{[]interface{}{}, []interface{}{[]interface{}{}}}}, "c7c0c1c0c3c0c1c0"},
+		{"long string", "Lorem ipsum dolor sit amet, consectetur adipisicing elit", "b8384c6f72656d20697073756d20646f6c6f722073697420616d65742c20636f6e7365637465747572206164697069736963696e6720656c6974"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded, err := EncodeRLP(tt.item)
+			if err != nil {
+				t.Fatalf("EncodeRLP failed: %v", err)
+			}
+			if got := hex.EncodeToString(encoded); got != tt.want {
+				t.Errorf("EncodeRLP = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeRLP(t *testing.T) {
+	decoded, err := DecodeRLP([]byte{0x83, 'd', 'o', 'g'})
+	if err != nil {
+		t.Fatalf("DecodeRLP failed: %v", err)
+	}
+	if !bytes.Equal(decoded.([]byte), []byte("dog")) {
+		t.Errorf("decoded = %v, want dog", decoded)
+	}
+
+	decoded, err = DecodeRLP([]byte{0xc0})
+	if err != nil {
+		t.Fatalf("DecodeRLP failed: %v", err)
+	}
+	if list, ok := decoded.([]interface{}); !ok || len(list) != 0 {
+		t.Errorf("decoded = %#v, want empty list", decoded)
+	}
+
+	decoded, err = DecodeRLP([]byte{0x80})
+	if err != nil {
+		t.Fatalf("DecodeRLP failed: %v", err)
+	}
+	if b, ok := decoded.([]byte); !ok || len(b) != 0 {
+		t.Errorf("decoded = %#v, want empty string", decoded)
+	}
+
+	encoded, _ := EncodeRLP([]interface{}{"cat", []interface{}{strings.Repeat("x", 60)}})
+	decoded, err = DecodeRLP(encoded)
+	if err != nil {
+		t.Fatalf("DecodeRLP round trip failed: %v", err)
+	}
+	if list := decoded.([]interface{}); string(list[0].([]byte)) != "cat" || len(list[1].([]interface{})[0].([]byte)) != 60 {
+		t.Errorf("round trip = %#v", decoded)
+	}
+
+	for _, input := range []string{"83646f", "8100", "c3646f"} {
+		data, _ := hex.DecodeString(input)
+		if _, err := DecodeRLP(data); err == nil {
+			t.Errorf("DecodeRLP(%s) succeeded, want error", input)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package web3
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"math/bits"
+)
+
+func pbkdf2SHA256(password, salt []byte, iterations, keyLen int) []byte {
+	prf := hmac.New(sha256.New, password)
+	blocks := (keyLen + sha256.Size - 1) / sha256.Size
+
+	key := make([]byte, 0, blocks*sha256.Size)
+	counter := make([]byte, 4)
+	u := make([]byte, sha256.Size)
+	t := make([]byte, sha256.Size)
+
+	for block := 1; block <= blocks; block++ {
+		binary.BigEndian.PutUint32(counter, uint32(block))
+		u = pbkdf2Round(prf, u[:0], salt, counter)
+		copy(t, u)
+
+		for i := 1; i < iterations; i++ {
+			u = pbkdf2Round(prf, u[:0], u)
+			for j := range t {
+				t[j] ^= u[j]
+			}
+		}
+		key = append(key, t...)
+	}
+
+	return key[:keyLen]
+}
+
+func pbkdf2Round(prf hash.Hash, out []byte, data ...[]byte) []byte {
+	prf.Reset()
+	for _, d := range data {
+		prf.Write(d)
+	}
+	return prf.Sum(out)
+}
+
+// scryptKey follows RFC 7914. n must be a power of two greater than one and
+// the memory used is 128*r*n bytes.
+func scryptKey(password, salt []byte, n, r, p, keyLen int) ([]byte, error) {
+	if n <= 1 || n&(n-1) != 0 {
+		return nil, fmt.Errorf("scrypt n must be a power of two greater than 1, got %d", n)
+	}
+	if r <= 0 || p <= 0 || uint64(r)*uint64(p) >= 1<<30 {
+		return nil, fmt.Errorf("invalid scrypt parameters r=%d p=%d", r, p)
+	}
+	if uint64(n) > (1<<31-1)/uint64(128*r) {
+		return nil, fmt.Errorf("scrypt parameters n=%d r=%d are too large", n, r)
+	}
+
+	blockWords := 32 * r
+	b := pbkdf2SHA256(password, salt, 1, p*128*r)
+
+	x := make([]uint32, blockWords)
+	y := make([]uint32, blockWords)
+	v := make([]uint32, blockWords*n)
+
+	for i := 0; i < p; i++ {
+		chunk := b[i*128*r : (i+1)*128*r]
+		for j := range x {
+			x[j] = binary.LittleEndian.Uint32(chunk[j*4:])
+		}
+		scryptROMix(x, y, v, n, r)
+		for j, word := range x {
+			binary.LittleEndian.PutUint32(chunk[j*4:], word)
+		}
+	}
+
+	return pbkdf2SHA256(password, b, 1, keyLen), nil
+}
+
+func scryptROMix(x, y, v []uint32, n, r int) {
+	blockWords := 32 * r
+	for i := 0; i < n; i++ {
+		copy(v[i*blockWords:], x)
+		scryptBlockMix(x, y, r)
+	}
+	for i := 0; i < n; i++ {
+		j := int(x[(2*r-1)*16] & uint32(n-1))
+		block := v[j*blockWords : (j+1)*blockWords]
+		for k := range x {
+			x[k] ^= block[k]
+		}
+		scryptBlockMix(x, y, r)
+	}
+}
+
+// scryptBlockMix writes even sub-blocks to the first half of the output and
+// odd ones to the second half, using y as scratch space.
+func scryptBlockMix(b, y []uint32, r int) {
+	var x [16]uint32
+	copy(x[:], b[(2*r-1)*16:])
+
+	for i := 0; i < 2*r; i++ {
+		for j := range x {
+			x[j] ^= b[i*16+j]
+		}
+		salsa208(&x)
+
+		offset := (i/2)*16 + (i%2)*r*16
+		copy(y[offset:], x[:])
+	}
+	copy(b, y)
+}
+
+func salsa208(block *[16]uint32) {
+	x := *block
+	for i := 0; i < 8; i += 2 {
+		x[4] ^= bits.RotateLeft32(x[0]+x[12], 7)
+		x[8] ^= bits.RotateLeft32(x[4]+x[0], 9)
+		x[12] ^= bits.RotateLeft32(x[8]+x[4], 13)
+		x[0] ^= bits.RotateLeft32(x[12]+x[8], 18)
+		x[9] ^= bits.RotateLeft32(x[5]+x[1], 7)
+		x[13] ^= bits.RotateLeft32(x[9]+x[5], 9)
+		x[1] ^= bits.RotateLeft32(x[13]+x[9], 13)
+		x[5] ^= bits.RotateLeft32(x[1]+x[13], 18)
+		x[14] ^= bits.RotateLeft32(x[10]+x[6], 7)
+		x[2] ^= bits.RotateLeft32(x[14]+x[10], 9)
+		x[6] ^= bits.RotateLeft32(x[2]+x[14], 13)
+		x[10] ^= bits.RotateLeft32(x[6]+x[2], 18)
+		x[3] ^= bits.RotateLeft32(x[15]+x[11], 7)
+		x[7] ^= bits.RotateLeft32(x[3]+x[15], 9)
+		x[11] ^= bits.RotateLeft32(x[7]+x[3], 13)
+		x[15] ^= bits.RotateLeft32(x[11]+x[7], 18)
+
+		x[1] ^= bits.RotateLeft32(x[0]+x[3], 7)
+		x[2] ^= bits.RotateLeft32(x[1]+x[0], 9)
+		x[3] ^= bits.RotateLeft32(x[2]+x[1], 13)
+		x[0] ^= bits.RotateLeft32(x[3]+x[2], 18)
+		x[6] ^= bits.RotateLeft32(x[5]+x[4], 7)
+		x[7] ^= bits.RotateLeft32(x[6]+x[5], 9)
+		x[4] ^= bits.RotateLeft32(x[7]+x[6], 13)
+		x[5] ^= bits.RotateLeft32(x[4]+x[7], 18)
+		x[11] ^= bits.RotateLeft32(x[10]+x[9], 7)
+		x[8] ^= bits.RotateLeft32(x[11]+x[10], 9)
+		x[9] ^= bits.RotateLeft32(x[8]+x[11], 13)
+		x[10] ^= bits.RotateLeft32(x[9]+x[8], 18)
+		x[12] ^= bits.RotateLeft32(x[15]+x[14], 7)
+		x[13] ^= bits.RotateLeft32(x[12]+x[15], 9)
+		x[14] ^= bits.RotateLeft32(x[13]+x[12], 13)
+		x[15] ^= bits.RotateLeft32(x[14]+x[13], 18)
+	}
+	for i := range block {
+		block[i] += x[i]
+	}
+}
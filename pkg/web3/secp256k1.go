@@ -0,0 +1,292 @@
+package web3
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+)
+
+var (
+	secp256k1P, _  = new(big.Int).SetString("fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2f", 16)
+	secp256k1N, _  = new(big.Int).SetString("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141", 16)
+	secp256k1Gx, _ = new(big.Int).SetString("79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798", 16)
+	secp256k1Gy, _ = new(big.Int).SetString("483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8", 16)
+	secp256k1B     = big.NewInt(7)
+)
+
+func secp256k1IsOnCurve(x, y *big.Int) bool {
+	if x == nil || y == nil {
+		return false
+	}
+	if x.Sign() < 0 || x.Cmp(secp256k1P) >= 0 || y.Sign() < 0 || y.Cmp(secp256k1P) >= 0 {
+		return false
+	}
+
+	lhs := new(big.Int).Mul(y, y)
+	lhs.Mod(lhs, secp256k1P)
+
+	return lhs.Cmp(secp256k1Rhs(x)) == 0
+}
+
+func secp256k1Rhs(x *big.Int) *big.Int {
+	rhs := new(big.Int).Mul(x, x)
+	rhs.Mul(rhs, x)
+	rhs.Add(rhs, secp256k1B)
+	return rhs.Mod(rhs, secp256k1P)
+}
+
+func secp256k1Add(x1, y1, x2, y2 *big.Int) (*big.Int, *big.Int) {
+	if x1 == nil {
+		return x2, y2
+	}
+	if x2 == nil {
+		return x1, y1
+	}
+
+	if x1.Cmp(x2) == 0 {
+		if y1.Cmp(y2) == 0 && y1.Sign() != 0 {
+			return secp256k1Double(x1, y1)
+		}
+		return nil, nil
+	}
+
+	dy := new(big.Int).Sub(y2, y1)
+	dx := new(big.Int).Sub(x2, x1)
+	dx.Mod(dx, secp256k1P)
+	lambda := dy.Mul(dy, dx.ModInverse(dx, secp256k1P))
+	lambda.Mod(lambda, secp256k1P)
+
+	return secp256k1FromLambda(lambda, x1, y1, x2)
+}
+
+func secp256k1Double(x, y *big.Int) (*big.Int, *big.Int) {
+	if x == nil || y.Sign() == 0 {
+		return nil, nil
+	}
+
+	num := new(big.Int).Mul(x, x)
+	num.Mul(num, big.NewInt(3))
+	den := new(big.Int).Lsh(y, 1)
+	den.Mod(den, secp256k1P)
+	lambda := num.Mul(num, den.ModInverse(den, secp256k1P))
+	lambda.Mod(lambda, secp256k1P)
+
+	return secp256k1FromLambda(lambda, x, y, x)
+}
+
+func secp256k1FromLambda(lambda, x1, y1, x2 *big.Int) (*big.Int, *big.Int) {
+	x3 := new(big.Int).Mul(lambda, lambda)
+	x3.Sub(x3, x1)
+	x3.Sub(x3, x2)
+	x3.Mod(x3, secp256k1P)
+
+	y3 := new(big.Int).Sub(x1, x3)
+	y3.Mul(y3, lambda)
+	y3.Sub(y3, y1)
+	y3.Mod(y3, secp256k1P)
+
+	return x3, y3
+}
+
+func secp256k1ScalarMult(x, y, k *big.Int) (*big.Int, *big.Int) {
+	var rx, ry *big.Int
+	for i := k.BitLen() - 1; i >= 0; i-- {
+		rx, ry = secp256k1Double(rx, ry)
+		if k.Bit(i) == 1 {
+			rx, ry = secp256k1Add(rx, ry, x, y)
+		}
+	}
+	return rx, ry
+}
+
+func secp256k1ScalarBaseMult(k *big.Int) (*big.Int, *big.Int) {
+	return secp256k1ScalarMult(secp256k1Gx, secp256k1Gy, k)
+}
+
+func RecoverPublicKey(hash [32]byte, signature []byte) (*PublicKey, error) {
+	if len(signature) != 65 {
+		return nil, fmt.Errorf("signature must be 65 bytes, got %d", len(signature))
+	}
+
+	r := new(big.Int).SetBytes(signature[:32])
+	s := new(big.Int).SetBytes(signature[32:64])
+	v := signature[64]
+	if v >= 27 {
+		v -= 27
+	}
+	if v > 1 {
+		return nil, fmt.Errorf("invalid recovery id %d", signature[64])
+	}
+
+	if r.Sign() == 0 || r.Cmp(secp256k1N) >= 0 {
+		return nil, fmt.Errorf("invalid signature r value")
+	}
+	if s.Sign() == 0 || s.Cmp(secp256k1N) >= 0 {
+		return nil, fmt.Errorf("invalid signature s value")
+	}
+
+	rx := new(big.Int).Set(r)
+	exp := new(big.Int).Add(secp256k1P, big.NewInt(1))
+	exp.Rsh(exp, 2)
+	ry := new(big.Int).Exp(secp256k1Rhs(rx), exp, secp256k1P)
+	if !secp256k1IsOnCurve(rx, ry) {
+		return nil, fmt.Errorf("signature r is not a valid curve point")
+	}
+	if ry.Bit(0) != uint(v) {
+		ry.Sub(secp256k1P, ry)
+	}
+
+	e := new(big.Int).SetBytes(hash[:])
+	e.Mod(e, secp256k1N)
+	rInv := new(big.Int).ModInverse(r, secp256k1N)
+
+	u1 := new(big.Int).Neg(e)
+	u1.Mul(u1, rInv)
+	u1.Mod(u1, secp256k1N)
+	u2 := new(big.Int).Mul(s, rInv)
+	u2.Mod(u2, secp256k1N)
+
+	x1, y1 := secp256k1ScalarBaseMult(u1)
+	x2, y2 := secp256k1ScalarMult(rx, ry, u2)
+	qx, qy := secp256k1Add(x1, y1, x2, y2)
+
+	if !secp256k1IsOnCurve(qx, qy) {
+		return nil, fmt.Errorf("recovered public key is not on the curve")
+	}
+
+	return &PublicKey{X: qx, Y: qy}, nil
+}
+
+func PublicKeyToAddress(pub *PublicKey) string {
+	buf := make([]byte, 64)
+	pub.X.FillBytes(buf[:32])
+	pub.Y.FillBytes(buf[32:])
+
+	return "0x" + hex.EncodeToString(keccak256(buf)[12:])
+}
+
+func ECRecover(hash [32]byte, signature []byte) (string, error) {
+	pub, err := RecoverPublicKey(hash, signature)
+	if err != nil {
+		return "", err
+	}
+	return PublicKeyToAddress(pub), nil
+}
+
+func parsePrivateKey(privateKeyHex string) (*big.Int, error) {
+	privateKeyBytes, err := normalizeHex(privateKeyHex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key format: %w", err)
+	}
+	if len(privateKeyBytes) != 32 {
+		return nil, fmt.Errorf("private key must be 32 bytes")
+	}
+
+	d := new(big.Int).SetBytes(privateKeyBytes)
+	if d.Sign() == 0 || d.Cmp(secp256k1N) >= 0 {
+		return nil, fmt.Errorf("private key out of range for secp256k1")
+	}
+	return d, nil
+}
+
+// signHashParity signs for the Ethereum formats, whose v only carries the y
+// parity: an x coordinate that overflowed the curve order (recovery id 2 or
+// 3) could not be recovered from the signature, so it is refused.
+func signHashParity(hash [32]byte, d *big.Int) (*big.Int, *big.Int, byte, error) {
+	r, s, recoveryID, err := signHash(hash, d)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	if recoveryID&2 != 0 {
+		return nil, nil, 0, fmt.Errorf("signature recovery id %d cannot be encoded in v", recoveryID)
+	}
+	return r, s, recoveryID, nil
+}
+
+func signHash(hash [32]byte, d *big.Int) (*big.Int, *big.Int, byte, error) {
+	halfN := new(big.Int).Rsh(secp256k1N, 1)
+	e := new(big.Int).SetBytes(hash[:])
+
+	nonces := newRFC6979Nonces(d, hash)
+	for i := 0; i < 100; i++ {
+		k := nonces.next()
+
+		rx, ry := secp256k1ScalarBaseMult(k)
+		r := new(big.Int).Mod(rx, secp256k1N)
+		if r.Sign() == 0 {
+			continue
+		}
+
+		s := new(big.Int).Mul(r, d)
+		s.Add(s, e)
+		s.Mul(s, new(big.Int).ModInverse(k, secp256k1N))
+		s.Mod(s, secp256k1N)
+		if s.Sign() == 0 {
+			continue
+		}
+
+		recoveryID := byte(ry.Bit(0))
+		if rx.Cmp(secp256k1N) >= 0 {
+			recoveryID |= 2
+		}
+		if s.Cmp(halfN) > 0 {
+			s.Sub(secp256k1N, s)
+			recoveryID ^= 1
+		}
+
+		return r, s, recoveryID, nil
+	}
+
+	return nil, nil, 0, fmt.Errorf("failed to generate signature nonce")
+}
+
+type rfc6979Nonces struct {
+	k, v []byte
+}
+
+func newRFC6979Nonces(d *big.Int, hash [32]byte) *rfc6979Nonces {
+	x := make([]byte, 32)
+	d.FillBytes(x)
+
+	h := new(big.Int).SetBytes(hash[:])
+	h.Mod(h, secp256k1N)
+	h1 := make([]byte, 32)
+	h.FillBytes(h1)
+
+	g := &rfc6979Nonces{
+		k: make([]byte, 32),
+		v: bytes.Repeat([]byte{0x01}, 32),
+	}
+
+	g.k = g.mac(g.v, []byte{0x00}, x, h1)
+	g.v = g.mac(g.v)
+	g.k = g.mac(g.v, []byte{0x01}, x, h1)
+	g.v = g.mac(g.v)
+
+	return g
+}
+
+func (g *rfc6979Nonces) mac(data ...[]byte) []byte {
+	m := hmac.New(sha256.New, g.k)
+	for _, d := range data {
+		m.Write(d)
+	}
+	return m.Sum(nil)
+}
+
+func (g *rfc6979Nonces) next() *big.Int {
+	for {
+		g.v = g.mac(g.v)
+		k := new(big.Int).SetBytes(g.v)
+
+		g.k = g.mac(g.v, []byte{0x00})
+		g.v = g.mac(g.v)
+
+		if k.Sign() > 0 && k.Cmp(secp256k1N) < 0 {
+			return k
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package web3
+
+import (
+	"encoding/hex"
+	"fmt"
+	"testing"
+)
+
+func TestRecoverPublicKeyGenerator(t *testing.T) {
+	// The public key of private key 1 is the generator point G.
+	hash := HashPersonalMessage([]byte("generator"))
+	r, s, recoveryID, err := signHash(hash, mustBig(t, "1"))
+	if err != nil {
+		t.Fatalf("signHash failed: %v", err)
+	}
+
+	pub, err := RecoverPublicKey(hash, signatureBytes(r, s, recoveryID))
+	if err != nil {
+		t.Fatalf("RecoverPublicKey failed: %v", err)
+	}
+
+	wantX := "79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798"
+	wantY := "483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8"
+	if got := fmt.Sprintf("%064x", pub.X); got != wantX {
+		t.Errorf("X = %s, want %s", got, wantX)
+	}
+	if got := fmt.Sprintf("%064x", pub.Y); got != wantY {
+		t.Errorf("Y = %s, want %s", got, wantY)
+	}
+}
+
+func TestRecoverPublicKeyKnownSignature(t *testing.T) {
+	// web3.js accounts.sign("Some data", key) reference signature.
+	key := "0x4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318"
+	signature, _ := hex.DecodeString("b91467e570a6466aa9e9876cbcd013baba02900b8979d43fe208a4a4f339f5fd6007e74cd82e037b800186422fc2da167c747ef045e5d18a5f5d4300f8e1a0291c")
+
+	pub, err := RecoverPublicKey(HashPersonalMessage([]byte("Some data")), signature)
+	if err != nil {
+		t.Fatalf("RecoverPublicKey failed: %v", err)
+	}
+
+	want, err := PrivateKeyToPublicKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pub.X.Cmp(want.X) != 0 || pub.Y.Cmp(want.Y) != 0 {
+		t.Errorf("recovered public key does not match the signing key")
+	}
+	if address := PublicKeyToAddress(pub); address != "0x2c7536e3605d9c16a7a3d7b1898e529396a65c23" {
+		t.Errorf("address = %s, want 0x2c7536e3605d9c16a7a3d7b1898e529396a65c23", address)
+	}
+}
+
+func TestRecoverPublicKeyRejectsInvalidSignatures(t *testing.T) {
+	hash := HashPersonalMessage([]byte("x"))
+	if _, err := RecoverPublicKey(hash, make([]byte, 64)); err == nil {
+		t.Error("expected error for 64-byte signature")
+	}
+	if _, err := RecoverPublicKey(hash, make([]byte, 65)); err == nil {
+		t.Error("expected error for zero r and s")
+	}
+}
+
+func TestGenerateRandomPrivateKeySecure(t *testing.T) {
+	seen := make(map[string]bool)
+	for i := 0; i < 16; i++ {
+		key, err := GenerateRandomPrivateKeySecure()
+		if err != nil {
+			t.Fatalf("GenerateRandomPrivateKeySecure failed: %v", err)
+		}
+		if !ValidatePrivateKey(key) {
+			t.Fatalf("generated key %s is not valid", key)
+		}
+		if _, err := PrivateKeyToAddress(key); err != nil {
+			t.Fatalf("generated key %s has no address: %v", key, err)
+		}
+		if seen[key] {
+			t.Fatalf("generated duplicate key %s", key)
+		}
+		seen[key] = true
+	}
+}
@@ -0,0 +1,130 @@
+package web3
+
+import (
+	"encoding/hex"
+	"fmt"
+	"sync"
+)
+
+var knownSignatures = []string{
+	"transfer(address,uint256)",
+	"transferFrom(address,address,uint256)",
+	"approve(address,uint256)",
+	"balanceOf(address)",
+	"allowance(address,address)",
+	"totalSupply()",
+	"name()",
+	"symbol()",
+	"decimals()",
+	"nonces(address)",
+	"DOMAIN_SEPARATOR()",
+	"permit(address,address,uint256,uint256,uint8,bytes32,bytes32)",
+	"permit(address,address,uint256,uint256,bool,uint8,bytes32,bytes32)",
+	"safeTransferFrom(address,address,uint256)",
+	"safeTransferFrom(address,address,uint256,bytes)",
+	"setApprovalForAll(address,bool)",
+	"ownerOf(uint256)",
+	"getApproved(uint256)",
+	"isApprovedForAll(address,address)",
+	"tokenURI(uint256)",
+	"balanceOf(address,uint256)",
+	"balanceOfBatch(address[],uint256[])",
+	"safeTransferFrom(address,address,uint256,uint256,bytes)",
+	"safeBatchTransferFrom(address,address,uint256[],uint256[],bytes)",
+	"deposit()",
+	"withdraw(uint256)",
+	"multiSend(bytes)",
+}
+
+type selectorRegistry struct {
+	mu         sync.RWMutex
+	signatures map[[4]byte]*ABIFunction
+}
+
+var defaultSelectorRegistry = newSelectorRegistry()
+
+func newSelectorRegistry() *selectorRegistry {
+	registry := &selectorRegistry{signatures: make(map[[4]byte]*ABIFunction)}
+	for _, signature := range knownSignatures {
+		if err := registry.register(signature); err != nil {
+			panic(err)
+		}
+	}
+	return registry
+}
+
+func FunctionSelector(signature string) ([4]byte, error) {
+	var selector [4]byte
+
+	fn, err := ParseABISignature(signature)
+	if err != nil {
+		return selector, err
+	}
+
+	copy(selector[:], keccak256([]byte(createFunctionSignature(fn.Name, fn.Inputs))))
+	return selector, nil
+}
+
+func RegisterSignature(signature string) error {
+	return defaultSelectorRegistry.register(signature)
+}
+
+func LookupSelector(selector [4]byte) (string, bool) {
+	fn, ok := defaultSelectorRegistry.lookup(selector)
+	if !ok {
+		return "", false
+	}
+	return createFunctionSignature(fn.Name, fn.Inputs), true
+}
+
+func DecodeCalldata(data []byte) (string, []interface{}, error) {
+	if len(data) < 4 {
+		return "", nil, fmt.Errorf("calldata too short for selector")
+	}
+
+	var selector [4]byte
+	copy(selector[:], data[:4])
+
+	fn, ok := defaultSelectorRegistry.lookup(selector)
+	if !ok {
+		return "", nil, fmt.Errorf("unknown selector 0x%s", hex.EncodeToString(selector[:]))
+	}
+	signature := createFunctionSignature(fn.Name, fn.Inputs)
+
+	if len(fn.Inputs) == 0 {
+		return signature, []interface{}{}, nil
+	}
+
+	types := make([]string, len(fn.Inputs))
+	for i, input := range fn.Inputs {
+		types[i] = input.canonicalType()
+	}
+	args, err := DecodeFunctionResult(types, data[4:])
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to decode %s arguments: %w", signature, err)
+	}
+
+	return signature, args, nil
+}
+
+func (r *selectorRegistry) register(signature string) error {
+	fn, err := ParseABISignature(signature)
+	if err != nil {
+		return err
+	}
+
+	var selector [4]byte
+	copy(selector[:], keccak256([]byte(createFunctionSignature(fn.Name, fn.Inputs))))
+
+	r.mu.Lock()
+	r.signatures[selector] = fn
+	r.mu.Unlock()
+	return nil
+}
+
+func (r *selectorRegistry) lookup(selector [4]byte) (*ABIFunction, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	fn, ok := r.signatures[selector]
+	return fn, ok
+}
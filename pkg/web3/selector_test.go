@@ -0,0 +1,60 @@
+package web3
+
+import (
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestFunctionSelectorTransfer(t *testing.T) {
+	for _, signature := range []string{
+		"transfer(address,uint256)",
+		"function transfer(address to, uint256 amount) external returns (bool)",
+		"transfer(address,uint)",
+	} {
+		selector, err := FunctionSelector(signature)
+		if err != nil {
+			t.Fatalf("FunctionSelector(%q) failed: %v", signature, err)
+		}
+		if got := hex.EncodeToString(selector[:]); got != "a9059cbb" {
+			t.Errorf("FunctionSelector(%q) = %s, want a9059cbb", signature, got)
+		}
+	}
+
+	signature, ok := LookupSelector([4]byte{0xa9, 0x05, 0x9c, 0xbb})
+	if !ok || signature != "transfer(address,uint256)" {
+		t.Errorf("LookupSelector = %q, %v", signature, ok)
+	}
+}
+
+func TestDecodeCalldataTransfer(t *testing.T) {
+	data, err := NewERC20Token(testTokenAddress, "", "", 6).EncodeTransfer(testSpender, big.NewInt(1_000_000))
+	if err != nil {
+		t.Fatalf("EncodeTransfer failed: %v", err)
+	}
+
+	signature, args, err := DecodeCalldata(data)
+	if err != nil {
+		t.Fatalf("DecodeCalldata failed: %v", err)
+	}
+	if signature != "transfer(address,uint256)" || len(args) != 2 {
+		t.Fatalf("decoded %s %v", signature, args)
+	}
+	if to, _ := args[0].(string); !strings.EqualFold(to, testSpender) {
+		t.Errorf("to = %v, want %s", args[0], testSpender)
+	}
+	if amount, ok := args[1].(*big.Int); !ok || amount.Int64() != 1_000_000 {
+		t.Errorf("amount = %v, want 1000000", args[1])
+	}
+
+	if _, _, err := DecodeCalldata([]byte{0xde, 0xad, 0xbe, 0xef}); err == nil || !strings.Contains(err.Error(), "unknown selector 0xdeadbeef") {
+		t.Errorf("error = %v, want unknown selector", err)
+	}
+	if _, _, err := DecodeCalldata(data[:3]); err == nil {
+		t.Error("expected error for calldata shorter than a selector")
+	}
+	if _, _, err := DecodeCalldata(data[:40]); err == nil {
+		t.Error("expected error for truncated arguments")
+	}
+}
@@ -0,0 +1,438 @@
+package web3
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+)
+
+type SignedTransaction struct {
+	Transaction *Transaction
+	ChainID     *big.Int
+	V           *big.Int
+	R           *big.Int
+	S           *big.Int
+	Raw         []byte
+}
+
+func SignTransaction(tx *Transaction, privateKeyHex string, chainID *big.Int) (*SignedTransaction, error) {
+	if tx == nil {
+		return nil, fmt.Errorf("transaction is required")
+	}
+	if chainID == nil || chainID.Sign() <= 0 {
+		return nil, fmt.Errorf("chain id must be positive")
+	}
+
+	d, err := parsePrivateKey(privateKeyHex)
+	if err != nil {
+		return nil, err
+	}
+
+	if tx.Type != LegacyTxType && tx.ChainID != nil && tx.ChainID.Cmp(chainID) != 0 {
+		return nil, fmt.Errorf("transaction chain id %s does not match signing chain id %s", tx.ChainID.String(), chainID.String())
+	}
+
+	hash, err := tx.SigningHash(chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	r, s, recoveryID, err := signHashParity(hash, d)
+	if err != nil {
+		return nil, err
+	}
+
+	var v *big.Int
+	if tx.Type == LegacyTxType {
+		v = new(big.Int).Mul(chainID, big.NewInt(2))
+		v.Add(v, big.NewInt(35+int64(recoveryID)))
+	} else {
+		v = big.NewInt(int64(recoveryID))
+	}
+
+	fields, prefix, err := tx.payloadFields(chainID)
+	if err != nil {
+		return nil, err
+	}
+	encoded, err := EncodeRLP(append(fields, v, r, s))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode signed transaction: %w", err)
+	}
+
+	return &SignedTransaction{
+		Transaction: tx,
+		ChainID:     chainID,
+		V:           v,
+		R:           r,
+		S:           s,
+		Raw:         append(prefix, encoded...),
+	}, nil
+}
+
+func (tx *Transaction) SigningHash(chainID *big.Int) ([32]byte, error) {
+	var hash [32]byte
+
+	if tx.Type != LegacyTxType && chainID == nil {
+		chainID = tx.ChainID
+		if chainID == nil {
+			return hash, fmt.Errorf("chain id is required for typed transactions")
+		}
+	}
+
+	fields, prefix, err := tx.payloadFields(chainID)
+	if err != nil {
+		return hash, err
+	}
+	if tx.Type == LegacyTxType && chainID != nil {
+		fields = append(fields, chainID, uint64(0), uint64(0))
+	}
+
+	encoded, err := EncodeRLP(fields)
+	if err != nil {
+		return hash, fmt.Errorf("failed to encode transaction: %w", err)
+	}
+
+	copy(hash[:], keccak256(prefix, encoded))
+	return hash, nil
+}
+
+func (tx *Transaction) payloadFields(chainID *big.Int) ([]interface{}, []byte, error) {
+	switch tx.Type {
+	case LegacyTxType:
+		fields, err := tx.rlpFields()
+		return fields, nil, err
+	case AccessListTxType:
+		fields, err := tx.accessListFields(chainID)
+		return fields, []byte{AccessListTxType}, err
+	case DynamicFeeTxType:
+		fields, err := tx.dynamicFeeFields(chainID)
+		return fields, []byte{DynamicFeeTxType}, err
+	default:
+		return nil, nil, fmt.Errorf("unsupported transaction type %d", tx.Type)
+	}
+}
+
+func (stx *SignedTransaction) RawHex() string {
+	return "0x" + hex.EncodeToString(stx.Raw)
+}
+
+func (stx *SignedTransaction) Hash() string {
+	return "0x" + hex.EncodeToString(keccak256(stx.Raw))
+}
+
+func RecoverSender(signedTxRawHex string) (string, error) {
+	raw, err := normalizeHex(signedTxRawHex)
+	if err != nil {
+		return "", fmt.Errorf("invalid raw transaction hex: %w", err)
+	}
+
+	if len(raw) > 0 && (raw[0] == AccessListTxType || raw[0] == DynamicFeeTxType) {
+		return recoverTypedSender(raw[0], raw[1:])
+	}
+
+	decoded, err := DecodeRLP(raw)
+	if err != nil {
+		return "", fmt.Errorf("invalid raw transaction: %w", err)
+	}
+
+	items, ok := decoded.([]interface{})
+	if !ok || len(items) != 9 {
+		return "", fmt.Errorf("raw transaction must be an rlp list of 9 items")
+	}
+
+	tx, err := decodeLegacyTransaction(items[:6])
+	if err != nil {
+		return "", err
+	}
+
+	v, err := rlpBigInt(items[6])
+	if err != nil {
+		return "", fmt.Errorf("invalid v: %w", err)
+	}
+	r, err := rlpBigInt(items[7])
+	if err != nil {
+		return "", fmt.Errorf("invalid r: %w", err)
+	}
+	s, err := rlpBigInt(items[8])
+	if err != nil {
+		return "", fmt.Errorf("invalid s: %w", err)
+	}
+
+	var chainID *big.Int
+	var recoveryID byte
+	switch {
+	case v.Cmp(big.NewInt(27)) == 0 || v.Cmp(big.NewInt(28)) == 0:
+		recoveryID = byte(v.Int64() - 27)
+	case v.Cmp(big.NewInt(35)) >= 0:
+		offset := new(big.Int).Sub(v, big.NewInt(35))
+		recoveryID = byte(offset.Bit(0))
+		chainID = offset.Rsh(offset, 1)
+	default:
+		return "", fmt.Errorf("invalid v value %s", v.String())
+	}
+
+	if err := validateTxSignatureValues(r, s); err != nil {
+		return "", err
+	}
+
+	hash, err := tx.SigningHash(chainID)
+	if err != nil {
+		return "", err
+	}
+
+	return ECRecover(hash, signatureBytes(r, s, recoveryID))
+}
+
+func recoverTypedSender(txType byte, payload []byte) (string, error) {
+	decoded, err := DecodeRLP(payload)
+	if err != nil {
+		return "", fmt.Errorf("invalid raw transaction: %w", err)
+	}
+
+	fieldCount := 9
+	if txType == AccessListTxType {
+		fieldCount = 8
+	}
+
+	items, ok := decoded.([]interface{})
+	if !ok || len(items) != fieldCount+3 {
+		return "", fmt.Errorf("type %d transaction must be an rlp list of %d items", txType, fieldCount+3)
+	}
+
+	var tx *Transaction
+	if txType == AccessListTxType {
+		tx, err = decodeAccessListTransaction(items[:fieldCount])
+	} else {
+		tx, err = decodeDynamicFeeTransaction(items[:fieldCount])
+	}
+	if err != nil {
+		return "", err
+	}
+
+	v, err := rlpUint64(items[fieldCount])
+	if err != nil || v > 1 {
+		return "", fmt.Errorf("invalid y parity")
+	}
+	r, err := rlpBigInt(items[fieldCount+1])
+	if err != nil {
+		return "", fmt.Errorf("invalid r: %w", err)
+	}
+	s, err := rlpBigInt(items[fieldCount+2])
+	if err != nil {
+		return "", fmt.Errorf("invalid s: %w", err)
+	}
+
+	if err := validateTxSignatureValues(r, s); err != nil {
+		return "", err
+	}
+
+	hash, err := tx.SigningHash(tx.ChainID)
+	if err != nil {
+		return "", err
+	}
+
+	return ECRecover(hash, signatureBytes(r, s, byte(v)))
+}
+
+func decodeAccessListTransaction(items []interface{}) (*Transaction, error) {
+	chainID, err := rlpBigInt(items[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid chain id: %w", err)
+	}
+
+	tx, err := decodeLegacyTransaction(items[1:7])
+	if err != nil {
+		return nil, err
+	}
+
+	accessList, err := rlpAccessList(items[7])
+	if err != nil {
+		return nil, fmt.Errorf("invalid access list: %w", err)
+	}
+
+	tx.Type = AccessListTxType
+	tx.ChainID = chainID
+	tx.AccessList = accessList
+	return tx, nil
+}
+
+func decodeDynamicFeeTransaction(items []interface{}) (*Transaction, error) {
+	chainID, err := rlpBigInt(items[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid chain id: %w", err)
+	}
+	nonce, err := rlpUint64(items[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid nonce: %w", err)
+	}
+	maxPriorityFee, err := rlpBigInt(items[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid max priority fee: %w", err)
+	}
+	maxFee, err := rlpBigInt(items[3])
+	if err != nil {
+		return nil, fmt.Errorf("invalid max fee: %w", err)
+	}
+	gas, err := rlpUint64(items[4])
+	if err != nil {
+		return nil, fmt.Errorf("invalid gas: %w", err)
+	}
+	to, err := rlpAddress(items[5])
+	if err != nil {
+		return nil, fmt.Errorf("invalid to: %w", err)
+	}
+	value, err := rlpBigInt(items[6])
+	if err != nil {
+		return nil, fmt.Errorf("invalid value: %w", err)
+	}
+	data, ok := items[7].([]byte)
+	if !ok {
+		return nil, fmt.Errorf("invalid data: expected bytes")
+	}
+	accessList, err := rlpAccessList(items[8])
+	if err != nil {
+		return nil, fmt.Errorf("invalid access list: %w", err)
+	}
+
+	return &Transaction{
+		Type:                 DynamicFeeTxType,
+		ChainID:              chainID,
+		Nonce:                nonce,
+		MaxPriorityFeePerGas: maxPriorityFee,
+		MaxFeePerGas:         maxFee,
+		Gas:                  gas,
+		To:                   to,
+		Value:                value,
+		Data:                 data,
+		AccessList:           accessList,
+	}, nil
+}
+
+func rlpAccessList(item interface{}) (AccessList, error) {
+	entries, ok := item.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected list")
+	}
+
+	list := make(AccessList, 0, len(entries))
+	for i, entryItem := range entries {
+		entry, ok := entryItem.([]interface{})
+		if !ok || len(entry) != 2 {
+			return nil, fmt.Errorf("entry %d must be a list of 2 items", i)
+		}
+
+		address, err := rlpAddress(entry[0])
+		if err != nil || address == "" {
+			return nil, fmt.Errorf("entry %d has invalid address", i)
+		}
+
+		keyItems, ok := entry[1].([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("entry %d storage keys must be a list", i)
+		}
+
+		keys := make([]string, 0, len(keyItems))
+		for _, keyItem := range keyItems {
+			key, ok := keyItem.([]byte)
+			if !ok || len(key) != 32 {
+				return nil, fmt.Errorf("entry %d has invalid storage key", i)
+			}
+			keys = append(keys, "0x"+hex.EncodeToString(key))
+		}
+
+		list = append(list, AccessListEntry{Address: address, StorageKeys: keys})
+	}
+	return list, nil
+}
+
+// validateTxSignatureValues applies the EIP-2 rules nodes enforce: r and s
+// must be in [1, n) and s must be in the lower half of the curve order.
+func validateTxSignatureValues(r, s *big.Int) error {
+	if r.Sign() <= 0 || r.Cmp(secp256k1N) >= 0 {
+		return fmt.Errorf("invalid signature r value")
+	}
+	if s.Sign() <= 0 || s.Cmp(new(big.Int).Rsh(secp256k1N, 1)) > 0 {
+		return fmt.Errorf("invalid signature s value")
+	}
+	return nil
+}
+
+func signatureBytes(r, s *big.Int, recoveryID byte) []byte {
+	signature := make([]byte, 65)
+	r.FillBytes(signature[:32])
+	s.FillBytes(signature[32:64])
+	signature[64] = recoveryID
+	return signature
+}
+
+func decodeLegacyTransaction(items []interface{}) (*Transaction, error) {
+	nonce, err := rlpUint64(items[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid nonce: %w", err)
+	}
+	gasPrice, err := rlpBigInt(items[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid gas price: %w", err)
+	}
+	gas, err := rlpUint64(items[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid gas: %w", err)
+	}
+	to, err := rlpAddress(items[3])
+	if err != nil {
+		return nil, fmt.Errorf("invalid to: %w", err)
+	}
+	value, err := rlpBigInt(items[4])
+	if err != nil {
+		return nil, fmt.Errorf("invalid value: %w", err)
+	}
+	data, ok := items[5].([]byte)
+	if !ok {
+		return nil, fmt.Errorf("invalid data: expected bytes")
+	}
+
+	return &Transaction{
+		Nonce:    nonce,
+		GasPrice: gasPrice,
+		Gas:      gas,
+		To:       to,
+		Value:    value,
+		Data:     data,
+	}, nil
+}
+
+func rlpBigInt(item interface{}) (*big.Int, error) {
+	b, ok := item.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("expected bytes, got list")
+	}
+	if len(b) > 0 && b[0] == 0 {
+		return nil, fmt.Errorf("non-canonical integer with leading zeros")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+func rlpUint64(item interface{}) (uint64, error) {
+	value, err := rlpBigInt(item)
+	if err != nil {
+		return 0, err
+	}
+	if !value.IsUint64() {
+		return 0, fmt.Errorf("integer overflows uint64")
+	}
+	return value.Uint64(), nil
+}
+
+func rlpAddress(item interface{}) (string, error) {
+	b, ok := item.([]byte)
+	if !ok {
+		return "", fmt.Errorf("expected bytes, got list")
+	}
+	if len(b) == 0 {
+		return "", nil
+	}
+	if len(b) != 20 {
+		return "", fmt.Errorf("address must be 20 bytes, got %d", len(b))
+	}
+	return "0x" + hex.EncodeToString(b), nil
+}
@@ -0,0 +1,90 @@
+package web3
+
+import (
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+// eip155Transaction is the example transaction from the EIP-155 specification.
+func eip155Transaction() *Transaction {
+	return &Transaction{
+		Nonce:    9,
+		GasPrice: big.NewInt(20000000000),
+		Gas:      21000,
+		To:       "0x3535353535353535353535353535353535353535",
+		Value:    big.NewInt(1000000000000000000),
+		Data:     []byte{},
+	}
+}
+
+const eip155PrivateKey = "0x4646464646464646464646464646464646464646464646464646464646464646"
+
+func TestSignTransactionEIP155Vector(t *testing.T) {
+	signed, err := SignTransaction(eip155Transaction(), eip155PrivateKey, big.NewInt(1))
+	if err != nil {
+		t.Fatalf("SignTransaction failed: %v", err)
+	}
+
+	want := "0xf86c098504a817c800825208943535353535353535353535353535353535353535880de0b6b3a76400008025a028ef61340bd939bc2195fe537567866003e1a15d3c71ff63e1590620aa636276a067cbe9d8997f761aecb703304b3800ccf555c9f3dc64214b297fb1966a3b6d83"
+	if got := signed.RawHex(); got != want {
+		t.Errorf("RawHex() = %s, want %s", got, want)
+	}
+	if signed.V.Int64() != 37 {
+		t.Errorf("v = %s, want 37", signed.V)
+	}
+}
+
+func TestRecoverSenderRoundTrip(t *testing.T) {
+	want, err := PrivateKeyToAddress(eip155PrivateKey)
+	if err != nil {
+		t.Fatalf("PrivateKeyToAddress failed: %v", err)
+	}
+
+	dynamic := eip155Transaction()
+	dynamic.Type = DynamicFeeTxType
+	dynamic.ChainID = big.NewInt(1)
+	dynamic.MaxFeePerGas = big.NewInt(30000000000)
+	dynamic.MaxPriorityFeePerGas = big.NewInt(1000000000)
+
+	for _, tx := range []*Transaction{eip155Transaction(), dynamic} {
+		signed, err := SignTransaction(tx, eip155PrivateKey, big.NewInt(1))
+		if err != nil {
+			t.Fatalf("SignTransaction failed: %v", err)
+		}
+		sender, err := RecoverSender(signed.RawHex())
+		if err != nil {
+			t.Fatalf("RecoverSender failed: %v", err)
+		}
+		if !strings.EqualFold(sender, want) {
+			t.Errorf("type %d sender = %s, want %s", tx.Type, sender, want)
+		}
+	}
+}
+
+func TestRecoverSenderRejectsInvalidSignatureValues(t *testing.T) {
+	fields, _, _ := eip155Transaction().payloadFields(nil)
+	oversized := new(big.Int).Lsh(big.NewInt(1), 300)
+	highS := new(big.Int).Sub(secp256k1N, big.NewInt(1))
+
+	tests := map[string][2]*big.Int{
+		"r wider than 256 bits": {oversized, big.NewInt(1)},
+		"s wider than 256 bits": {big.NewInt(1), oversized},
+		"zero r":                {big.NewInt(0), big.NewInt(1)},
+		"r equal to n":          {secp256k1N, big.NewInt(1)},
+		"high s":                {big.NewInt(1), highS},
+	}
+
+	for name, rs := range tests {
+		t.Run(name, func(t *testing.T) {
+			encoded, err := EncodeRLP(append(append([]interface{}{}, fields...), big.NewInt(37), rs[0], rs[1]))
+			if err != nil {
+				t.Fatalf("EncodeRLP failed: %v", err)
+			}
+			if _, err := RecoverSender("0x" + hex.EncodeToString(encoded)); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
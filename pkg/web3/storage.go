@@ -0,0 +1,35 @@
+package web3
+
+import (
+	"fmt"
+	"math/big"
+)
+
+func MappingSlot(key []byte, baseSlot *big.Int) (*big.Int, error) {
+	if len(key) > 32 {
+		return nil, fmt.Errorf("mapping key must be at most 32 bytes, got %d", len(key))
+	}
+	if baseSlot == nil || baseSlot.Sign() < 0 || baseSlot.BitLen() > 256 {
+		return nil, fmt.Errorf("base slot must be a uint256")
+	}
+
+	keyWord := make([]byte, 32)
+	copy(keyWord[32-len(key):], key)
+
+	slotWord := make([]byte, 32)
+	baseSlot.FillBytes(slotWord)
+
+	return new(big.Int).SetBytes(keccak256(keyWord, slotWord)), nil
+}
+
+func NestedMappingSlot(keys [][]byte, baseSlot *big.Int) (*big.Int, error) {
+	slot := baseSlot
+	for i, key := range keys {
+		next, err := MappingSlot(key, slot)
+		if err != nil {
+			return nil, fmt.Errorf("mapping key %d: %w", i, err)
+		}
+		slot = next
+	}
+	return slot, nil
+}
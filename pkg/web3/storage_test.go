@@ -0,0 +1,57 @@
+package web3
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+)
+
+func TestNestedMappingSlotAllowance(t *testing.T) {
+	owner, _ := hex.DecodeString("742d35cc6634c0532925a3b8d82c28d53e01bcf2")
+	spender, _ := hex.DecodeString("7a250d5630b4cf539739df2c5dacb4c659f2488d")
+	baseSlot := big.NewInt(1)
+
+	// allowance[owner][spender] lives at keccak(spender . keccak(owner . 1)).
+	word := func(b []byte) []byte { return append(make([]byte, 32-len(b)), b...) }
+	inner := keccak256(word(owner), word(baseSlot.Bytes()))
+	want := new(big.Int).SetBytes(keccak256(word(spender), inner))
+
+	slot, err := NestedMappingSlot([][]byte{owner, spender}, baseSlot)
+	if err != nil {
+		t.Fatalf("NestedMappingSlot failed: %v", err)
+	}
+	if slot.Cmp(want) != 0 {
+		t.Fatalf("slot = %x, want %x", slot, want)
+	}
+
+	wantKey := fmt.Sprintf("0x%064x", want)
+	client, _ := newFakeClient(func(method string, params []json.RawMessage) (interface{}, *RPCError) {
+		var key string
+		json.Unmarshal(params[1], &key)
+		if method != "eth_getStorageAt" || key != wantKey {
+			t.Errorf("unexpected request %s %s", method, key)
+		}
+		return "0x00000000000000000000000000000000000000000000000000000000000003e8", nil
+	})
+
+	value, err := client.GetStorageAt(context.Background(), "0x1111111111111111111111111111111111111111", slot, "latest")
+	if err != nil {
+		t.Fatalf("GetStorageAt failed: %v", err)
+	}
+	if new(big.Int).SetBytes(value).Int64() != 1000 {
+		t.Errorf("allowance = %x, want 1000", value)
+	}
+}
+
+func TestMappingSlotRejectsLongKeys(t *testing.T) {
+	if _, err := MappingSlot(bytes.Repeat([]byte{1}, 33), big.NewInt(0)); err == nil {
+		t.Fatal("expected error for 33-byte key")
+	}
+	if _, err := NestedMappingSlot([][]byte{{1}, bytes.Repeat([]byte{1}, 40)}, big.NewInt(0)); err == nil {
+		t.Fatal("expected error for nested 40-byte key")
+	}
+}
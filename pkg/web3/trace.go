@@ -0,0 +1,116 @@
+package web3
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+)
+
+type CallTrace struct {
+	Type         string
+	From         string
+	To           string
+	Value        *big.Int
+	Gas          uint64
+	GasUsed      uint64
+	Input        string
+	Output       string
+	Error        string
+	RevertReason string
+	Calls        []CallTrace
+}
+
+type rpcCallTrace struct {
+	Type         string         `json:"type"`
+	From         string         `json:"from"`
+	To           string         `json:"to"`
+	Value        string         `json:"value"`
+	Gas          string         `json:"gas"`
+	GasUsed      string         `json:"gasUsed"`
+	Input        string         `json:"input"`
+	Output       string         `json:"output"`
+	Error        string         `json:"error"`
+	RevertReason string         `json:"revertReason"`
+	Calls        []rpcCallTrace `json:"calls"`
+}
+
+func (c *Client) TraceTransaction(ctx context.Context, txHash string) (*CallTrace, error) {
+	result, err := c.CallContext(ctx, "debug_traceTransaction", txHash, map[string]interface{}{
+		"tracer": "callTracer",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeCallTrace(result)
+}
+
+func decodeCallTrace(result json.RawMessage) (*CallTrace, error) {
+	if string(result) == "null" {
+		return nil, fmt.Errorf("trace not found")
+	}
+
+	var raw rpcCallTrace
+	if err := json.Unmarshal(result, &raw); err != nil {
+		return nil, fmt.Errorf("invalid trace response: %w", err)
+	}
+
+	return raw.toCallTrace()
+}
+
+func (t *rpcCallTrace) toCallTrace() (*CallTrace, error) {
+	trace := &CallTrace{
+		Type:         t.Type,
+		From:         t.From,
+		To:           t.To,
+		Value:        new(big.Int),
+		Input:        t.Input,
+		Output:       t.Output,
+		Error:        t.Error,
+		RevertReason: t.RevertReason,
+		Calls:        make([]CallTrace, 0, len(t.Calls)),
+	}
+
+	var err error
+	if t.Value != "" {
+		if trace.Value, err = decodeHexBig(t.Value); err != nil {
+			return nil, fmt.Errorf("invalid trace value: %w", err)
+		}
+	}
+	if t.Gas != "" {
+		if trace.Gas, err = decodeHexUint64(t.Gas); err != nil {
+			return nil, fmt.Errorf("invalid trace gas: %w", err)
+		}
+	}
+	if t.GasUsed != "" {
+		if trace.GasUsed, err = decodeHexUint64(t.GasUsed); err != nil {
+			return nil, fmt.Errorf("invalid trace gas used: %w", err)
+		}
+	}
+
+	for i := range t.Calls {
+		call, err := t.Calls[i].toCallTrace()
+		if err != nil {
+			return nil, fmt.Errorf("invalid trace call %d: %w", i, err)
+		}
+		trace.Calls = append(trace.Calls, *call)
+	}
+
+	return trace, nil
+}
+
+func (t *CallTrace) Failed() bool {
+	return t.Error != ""
+}
+
+func (t *CallTrace) Walk(fn func(call *CallTrace, depth int)) {
+	t.walk(fn, 0)
+}
+
+func (t *CallTrace) walk(fn func(call *CallTrace, depth int), depth int) {
+	fn(t, depth)
+	for i := range t.Calls {
+		t.Calls[i].walk(fn, depth+1)
+	}
+}
@@ -0,0 +1,107 @@
+package web3
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+// nestedCallTrace is shaped like geth's callTracer output for a router call
+// that transfers a token and then reverts in a delegatecall.
+const nestedCallTrace = `{
+	"type": "CALL",
+	"from": "0x742d35cc6634c0532925a3b8d82c28d53e01bcf2",
+	"to": "0x7a250d5630b4cf539739df2c5dacb4c659f2488d",
+	"value": "0xde0b6b3a7640000",
+	"gas": "0x30d40",
+	"gasUsed": "0x1a2b3",
+	"input": "0x7ff36ab5",
+	"output": "0x",
+	"calls": [
+		{
+			"type": "CALL",
+			"from": "0x7a250d5630b4cf539739df2c5dacb4c659f2488d",
+			"to": "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48",
+			"gas": "0x2710",
+			"gasUsed": "0x1f40",
+			"input": "0xa9059cbb",
+			"output": "0x0000000000000000000000000000000000000000000000000000000000000001",
+			"calls": [
+				{
+					"type": "DELEGATECALL",
+					"from": "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48",
+					"to": "0x43506849d7c04f9138d1a2050bbf3a0c054402dd",
+					"gas": "0x1388",
+					"gasUsed": "0xfa0",
+					"input": "0xa9059cbb",
+					"error": "execution reverted",
+					"revertReason": "blacklisted"
+				}
+			]
+		},
+		{
+			"type": "STATICCALL",
+			"from": "0x7a250d5630b4cf539739df2c5dacb4c659f2488d",
+			"to": "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48",
+			"gas": "0x1000",
+			"gasUsed": "0x200",
+			"input": "0x70a08231"
+		}
+	]
+}`
+
+func TestTraceTransactionNested(t *testing.T) {
+	client, _ := newFakeClient(func(method string, params []json.RawMessage) (interface{}, *RPCError) {
+		var options map[string]string
+		json.Unmarshal(params[1], &options)
+		if method != "debug_traceTransaction" || options["tracer"] != "callTracer" {
+			t.Fatalf("unexpected call %s %s", method, params)
+		}
+		return json.RawMessage(nestedCallTrace), nil
+	})
+
+	trace, err := client.TraceTransaction(context.Background(), testTxHash)
+	if err != nil {
+		t.Fatalf("TraceTransaction failed: %v", err)
+	}
+	if trace.Value.String() != "1000000000000000000" || trace.Gas != 200000 || trace.GasUsed != 0x1a2b3 {
+		t.Errorf("root = %+v", trace)
+	}
+	if trace.Failed() {
+		t.Error("root call should not be marked failed")
+	}
+	if len(trace.Calls) != 2 || len(trace.Calls[0].Calls) != 1 {
+		t.Fatalf("unexpected call tree %+v", trace.Calls)
+	}
+	if trace.Calls[0].Value.Sign() != 0 {
+		t.Errorf("missing value should decode as zero, got %s", trace.Calls[0].Value)
+	}
+
+	inner := trace.Calls[0].Calls[0]
+	if inner.Type != "DELEGATECALL" || !inner.Failed() || inner.RevertReason != "blacklisted" {
+		t.Errorf("inner call = %+v", inner)
+	}
+
+	var types []string
+	var depths []int
+	trace.Walk(func(call *CallTrace, depth int) {
+		types = append(types, call.Type)
+		depths = append(depths, depth)
+	})
+	wantTypes := []string{"CALL", "CALL", "DELEGATECALL", "STATICCALL"}
+	wantDepths := []int{0, 1, 2, 1}
+	for i := range wantTypes {
+		if i >= len(types) || types[i] != wantTypes[i] || depths[i] != wantDepths[i] {
+			t.Fatalf("walk = %v %v, want %v %v", types, depths, wantTypes, wantDepths)
+		}
+	}
+}
+
+func TestDecodeCallTraceErrors(t *testing.T) {
+	if _, err := decodeCallTrace(json.RawMessage("null")); err == nil {
+		t.Error("expected error for a missing trace")
+	}
+	if _, err := decodeCallTrace(json.RawMessage(`{"type":"CALL","calls":[{"gas":"0xzz"}]}`)); err == nil {
+		t.Error("expected error for an invalid nested gas value")
+	}
+}
@@ -1,30 +1,90 @@
 package web3
 
 import (
+	"crypto/rand"
+	"encoding/binary"
 	"encoding/hex"
 	"fmt"
 	"math/big"
 	"strings"
 )
 
+const (
+	LegacyTxType     = 0
+	AccessListTxType = 1
+	DynamicFeeTxType = 2
+)
+
+const (
+	AccessListAddressGas    = 2400
+	AccessListStorageKeyGas = 1900
+)
+
 type Transaction struct {
-	To       string
-	Value    *big.Int
-	Gas      uint64
-	GasPrice *big.Int
-	Data     []byte
-	Nonce    uint64
+	Type                 uint8
+	ChainID              *big.Int
+	From                 string
+	To                   string
+	Value                *big.Int
+	Gas                  uint64
+	GasPrice             *big.Int
+	MaxFeePerGas         *big.Int
+	MaxPriorityFeePerGas *big.Int
+	Data                 []byte
+	Nonce                uint64
+	AccessList           AccessList
+}
+
+type AccessListEntry struct {
+	Address     string   `json:"address"`
+	StorageKeys []string `json:"storageKeys"`
+}
+
+type AccessList []AccessListEntry
+
+func (list AccessList) rlpItems() ([]interface{}, error) {
+	items := make([]interface{}, 0, len(list))
+	for i, entry := range list {
+		address, err := addressToBytes(entry.Address)
+		if err != nil || len(address) == 0 {
+			return nil, fmt.Errorf("invalid access list address at index %d", i)
+		}
+
+		keys := make([]interface{}, 0, len(entry.StorageKeys))
+		for _, key := range entry.StorageKeys {
+			keyBytes, err := normalizeHex(key)
+			if err != nil || len(keyBytes) != 32 {
+				return nil, fmt.Errorf("invalid storage key %s at index %d", key, i)
+			}
+			keys = append(keys, keyBytes)
+		}
+
+		items = append(items, []interface{}{address, keys})
+	}
+	return items, nil
+}
+
+func (list AccessList) Gas() uint64 {
+	gas := uint64(0)
+	for _, entry := range list {
+		gas += AccessListAddressGas
+		gas += uint64(len(entry.StorageKeys)) * AccessListStorageKeyGas
+	}
+	return gas
 }
 
 type TransactionReceipt struct {
-	Hash              string
-	BlockNumber       *big.Int
-	BlockHash         string
-	TransactionIndex  uint
-	From              string
-	To                string
-	GasUsed           uint64
+	Hash             string
+	BlockNumber      *big.Int
+	BlockHash        string
+	TransactionIndex uint
+	From             string
+	To               string
+	GasUsed          uint64
+	// Status is only meaningful when HasStatus is set. Pre-Byzantium receipts
+	// and some nodes omit it, which must not be mistaken for a revert.
 	Status            uint64
+	HasStatus         bool
 	ContractAddress   string
 	Logs              []Log
 	CumulativeGasUsed uint64
@@ -41,71 +101,231 @@ type Log struct {
 	LogIndex    uint
 }
 
-func EstimateGas(to, from, data string, value *big.Int) (uint64, error) {
-	if data == "" && value != nil && value.Cmp(big.NewInt(0)) > 0 {
-		return 21000, nil
-	}
+const (
+	txBaseGas           = 21000
+	contractCreationGas = 32000
+	initCodeWordGas     = 2
+)
 
+func EstimateGasOffline(to, from, data string, value *big.Int) (uint64, error) {
+	gas := uint64(txBaseGas)
+
+	var dataBytes []byte
 	if data != "" {
-		dataBytes, err := hex.DecodeString(strings.TrimPrefix(data, "0x"))
+		var err error
+		dataBytes, err = normalizeHex(data)
 		if err != nil {
 			return 0, fmt.Errorf("invalid data format: %w", err)
 		}
+		gas += calldataGas(dataBytes)
+	}
 
-		baseGas := uint64(21000)
-		dataGas := uint64(len(dataBytes)) * 16
+	// An empty to deploys a contract: the creation cost plus the EIP-3860
+	// charge per 32-byte word of init code.
+	if to == "" {
+		words := (uint64(len(dataBytes)) + 31) / 32
+		gas += contractCreationGas + words*initCodeWordGas
+	}
 
-		for _, b := range dataBytes {
-			if b == 0 {
-				dataGas += 4
-			} else {
-				dataGas += 16
-			}
-		}
+	return gas, nil
+}
 
-		return baseGas + dataGas, nil
+func EstimateGasWithAccessList(to, from, data string, value *big.Int, accessList AccessList) (uint64, error) {
+	gas, err := EstimateGasOffline(to, from, data, value)
+	if err != nil {
+		return 0, err
 	}
-
-	return 21000, nil
+	return gas + accessList.Gas(), nil
 }
 
-func SuggestGasPrice() *big.Int {
+func SuggestGasPriceDefault() *big.Int {
 	return big.NewInt(20000000000)
 }
 
 func CreateTransaction(to string, value *big.Int, data []byte) *Transaction {
-	gasLimit, _ := EstimateGas(to, "", hex.EncodeToString(data), value)
+	gasLimit, _ := EstimateGasOffline(to, "", hex.EncodeToString(data), value)
 
 	return &Transaction{
 		To:       to,
 		Value:    value,
 		Gas:      gasLimit,
-		GasPrice: SuggestGasPrice(),
+		GasPrice: SuggestGasPriceDefault(),
 		Data:     data,
 	}
 }
 
+// CalculateFee has no base fee to work with, so for dynamic fee transactions
+// it returns the upper bound Gas*MaxFeePerGas.
 func (tx *Transaction) CalculateFee() *big.Int {
-	return new(big.Int).Mul(big.NewInt(int64(tx.Gas)), tx.GasPrice)
+	return tx.CalculateFeeWithBaseFee(nil)
 }
 
+// EffectiveGasPrice treats a nil base fee as unknown and returns the fee cap.
+// Missing prices count as zero.
+func (tx *Transaction) EffectiveGasPrice(baseFee *big.Int) *big.Int {
+	if tx.Type != DynamicFeeTxType || tx.MaxFeePerGas == nil {
+		if tx.GasPrice == nil {
+			return new(big.Int)
+		}
+		return new(big.Int).Set(tx.GasPrice)
+	}
+	if baseFee == nil {
+		return new(big.Int).Set(tx.MaxFeePerGas)
+	}
+
+	price := new(big.Int).Set(baseFee)
+	if tx.MaxPriorityFeePerGas != nil {
+		price.Add(price, tx.MaxPriorityFeePerGas)
+	}
+	if price.Cmp(tx.MaxFeePerGas) > 0 {
+		price.Set(tx.MaxFeePerGas)
+	}
+	return price
+}
+
+func (tx *Transaction) CalculateFeeWithBaseFee(baseFee *big.Int) *big.Int {
+	return new(big.Int).Mul(new(big.Int).SetUint64(tx.Gas), tx.EffectiveGasPrice(baseFee))
+}
+
+func (tx *Transaction) MaxCost() *big.Int {
+	feeCap := tx.GasPrice
+	if tx.MaxFeePerGas != nil {
+		feeCap = tx.MaxFeePerGas
+	}
+
+	cost := new(big.Int)
+	if feeCap != nil {
+		cost.Mul(new(big.Int).SetUint64(tx.Gas), feeCap)
+	}
+	if tx.Value != nil {
+		cost.Add(cost, tx.Value)
+	}
+	return cost
+}
+
+// Hash returns an empty string when the transaction cannot be encoded, for
+// example because To is not a valid address; ComputeHash reports the reason.
 func (tx *Transaction) Hash() string {
-	return fmt.Sprintf("0x%x", tx.calculateHash())
+	hash, err := tx.ComputeHash()
+	if err != nil {
+		return ""
+	}
+	return hash
 }
 
-func (tx *Transaction) calculateHash() []byte {
-	data := fmt.Sprintf("%s%s%d%s%x%d",
-		tx.To,
-		tx.Value.String(),
+func (tx *Transaction) ComputeHash() (string, error) {
+	var chainID *big.Int
+	if tx.Type != LegacyTxType {
+		chainID = tx.ChainID
+	}
+
+	hash, err := tx.SigningHash(chainID)
+	if err != nil {
+		return "", err
+	}
+	return "0x" + hex.EncodeToString(hash[:]), nil
+}
+
+// IntentHash identifies the logical transfer independent of gas pricing, so
+// fee-bumped replacements of the same transaction share an identity. It hashes
+// the raw fields with addresses lowercased, so it cannot fail; malformed
+// addresses are caught when the transaction is signed.
+func (tx *Transaction) IntentHash() [32]byte {
+	value := "0"
+	if tx.Value != nil {
+		value = tx.Value.String()
+	}
+
+	var hash [32]byte
+	copy(hash[:], keccak256(
+		encodeRLPString([]byte(canonicalIntentAddress(tx.From))),
+		encodeRLPString([]byte(canonicalIntentAddress(tx.To))),
+		encodeRLPString([]byte(value)),
+		encodeRLPString(tx.Data),
+		encodeRLPString(new(big.Int).SetUint64(tx.Nonce).Bytes()),
+	))
+	return hash
+}
+
+func canonicalIntentAddress(address string) string {
+	address = strings.ToLower(strings.TrimSpace(address))
+	if address != "" && !strings.HasPrefix(address, "0x") {
+		address = "0x" + address
+	}
+	return address
+}
+
+func (tx *Transaction) rlpFields() ([]interface{}, error) {
+	to, err := addressToBytes(tx.To)
+	if err != nil {
+		return nil, err
+	}
+
+	return []interface{}{
+		tx.Nonce,
+		tx.GasPrice,
 		tx.Gas,
-		tx.GasPrice.String(),
+		to,
+		tx.Value,
 		tx.Data,
+	}, nil
+}
+
+func (tx *Transaction) accessListFields(chainID *big.Int) ([]interface{}, error) {
+	to, err := addressToBytes(tx.To)
+	if err != nil {
+		return nil, err
+	}
+
+	accessList, err := tx.AccessList.rlpItems()
+	if err != nil {
+		return nil, err
+	}
+
+	return []interface{}{
+		chainID,
 		tx.Nonce,
-	)
+		tx.GasPrice,
+		tx.Gas,
+		to,
+		tx.Value,
+		tx.Data,
+		accessList,
+	}, nil
+}
 
-	hash := make([]byte, 32)
-	copy(hash, []byte(data))
-	return hash
+func (tx *Transaction) dynamicFeeFields(chainID *big.Int) ([]interface{}, error) {
+	to, err := addressToBytes(tx.To)
+	if err != nil {
+		return nil, err
+	}
+
+	accessList, err := tx.AccessList.rlpItems()
+	if err != nil {
+		return nil, err
+	}
+
+	return []interface{}{
+		chainID,
+		tx.Nonce,
+		tx.MaxPriorityFeePerGas,
+		tx.MaxFeePerGas,
+		tx.Gas,
+		to,
+		tx.Value,
+		tx.Data,
+		accessList,
+	}, nil
+}
+
+func addressToBytes(address string) ([]byte, error) {
+	if address == "" {
+		return []byte{}, nil
+	}
+	if !ValidateAddress(address) {
+		return nil, fmt.Errorf("invalid address: %s", address)
+	}
+	return hex.DecodeString(address[2:])
 }
 
 func ValidateAddress(address string) bool {
@@ -121,47 +341,43 @@ func ValidateAddress(address string) bool {
 	return err == nil
 }
 
-func ValidatePrivateKey(privateKey string) bool {
-	if strings.HasPrefix(privateKey, "0x") {
-		privateKey = privateKey[2:]
-	}
-
-	if len(privateKey) != 64 {
-		return false
-	}
+func IsZeroAddress(address string) bool {
+	return ValidateAddress(address) && strings.TrimLeft(address[2:], "0") == ""
+}
 
-	_, err := hex.DecodeString(privateKey)
-	return err == nil
+func ValidatePrivateKey(privateKey string) bool {
+	key, err := normalizeHex(privateKey)
+	return err == nil && len(key) == 32
 }
 
 func PrivateKeyToAddress(privateKeyHex string) (string, error) {
-	if strings.HasPrefix(privateKeyHex, "0x") {
-		privateKeyHex = privateKeyHex[2:]
-	}
-
-	privateKeyBytes, err := hex.DecodeString(privateKeyHex)
+	pub, err := PrivateKeyToPublicKey(privateKeyHex)
 	if err != nil {
-		return "", fmt.Errorf("invalid private key format: %w", err)
-	}
-
-	if len(privateKeyBytes) != 32 {
-		return "", fmt.Errorf("private key must be 32 bytes")
+		return "", err
 	}
+	return PublicKeyToAddress(pub), nil
+}
 
-	address := make([]byte, 20)
-	for i := 0; i < 20; i++ {
-		address[i] = privateKeyBytes[i%32] ^ privateKeyBytes[(i+12)%32]
+func GenerateRandomPrivateKey() string {
+	privateKey, err := GenerateRandomPrivateKeySecure()
+	if err != nil {
+		panic(err)
 	}
-
-	return "0x" + hex.EncodeToString(address), nil
+	return privateKey
 }
 
-func GenerateRandomPrivateKey() string {
+func GenerateRandomPrivateKeySecure() (string, error) {
 	privateKey := make([]byte, 32)
-	for i := range privateKey {
-		privateKey[i] = byte(i*7 + 42)
+	for {
+		if _, err := rand.Read(privateKey); err != nil {
+			return "", fmt.Errorf("failed to read random bytes: %w", err)
+		}
+
+		d := new(big.Int).SetBytes(privateKey)
+		if d.Sign() > 0 && d.Cmp(secp256k1N) < 0 {
+			return "0x" + hex.EncodeToString(privateKey), nil
+		}
 	}
-	return "0x" + hex.EncodeToString(privateKey)
 }
 
 type PublicKey struct {
@@ -169,17 +385,73 @@ type PublicKey struct {
 }
 
 func PrivateKeyToPublicKey(privateKeyHex string) (*PublicKey, error) {
-	if strings.HasPrefix(privateKeyHex, "0x") {
-		privateKeyHex = privateKeyHex[2:]
+	d, err := parsePrivateKey(privateKeyHex)
+	if err != nil {
+		return nil, err
 	}
 
-	privateKeyBytes, err := hex.DecodeString(privateKeyHex)
-	if err != nil {
-		return nil, fmt.Errorf("invalid private key format: %w", err)
+	x, y := secp256k1ScalarBaseMult(d)
+	return &PublicKey{X: x, Y: y}, nil
+}
+
+func ValidateTransactionBatch(txs []*Transaction) error {
+	seen := make(map[uint64]int)
+
+	for i, tx := range txs {
+		if tx == nil {
+			return fmt.Errorf("transaction %d is nil", i)
+		}
+		if tx.Value == nil {
+			return fmt.Errorf("transaction %d has nil value", i)
+		}
+		if tx.Type == DynamicFeeTxType {
+			if tx.MaxFeePerGas == nil {
+				return fmt.Errorf("transaction %d has nil max fee per gas", i)
+			}
+		} else if tx.GasPrice == nil {
+			return fmt.Errorf("transaction %d has nil gas price", i)
+		}
+
+		if prev, exists := seen[tx.Nonce]; exists {
+			return fmt.Errorf("transaction %d reuses nonce %d of transaction %d", i, tx.Nonce, prev)
+		}
+		seen[tx.Nonce] = i
+
+		if i > 0 && tx.Nonce < txs[i-1].Nonce {
+			return fmt.Errorf("transaction %d has nonce %d lower than previous nonce %d", i, tx.Nonce, txs[i-1].Nonce)
+		}
 	}
 
-	x := new(big.Int).SetBytes(privateKeyBytes[:16])
-	y := new(big.Int).SetBytes(privateKeyBytes[16:])
+	return nil
+}
+
+// GenerateTestTransactions derives recipients and values from seed, so the
+// same arguments always produce the same batch.
+func GenerateTestTransactions(n int, from string, startNonce uint64, seed int64) []*Transaction {
+	txs := make([]*Transaction, 0, n)
 
-	return &PublicKey{X: x, Y: y}, nil
+	seedBytes := make([]byte, 8)
+	binary.BigEndian.PutUint64(seedBytes, uint64(seed))
+
+	for i := 0; i < n; i++ {
+		nonce := startNonce + uint64(i)
+
+		nonceBytes := make([]byte, 8)
+		binary.BigEndian.PutUint64(nonceBytes, nonce)
+		digest := keccak256(seedBytes, []byte(strings.ToLower(from)), nonceBytes)
+
+		value := new(big.Int).Mul(big.NewInt(int64(digest[0])+1), big.NewInt(1e15))
+
+		txs = append(txs, &Transaction{
+			From:     from,
+			To:       "0x" + hex.EncodeToString(digest[12:]),
+			Value:    value,
+			Gas:      txBaseGas,
+			GasPrice: SuggestGasPriceDefault(),
+			Data:     []byte{},
+			Nonce:    nonce,
+		})
+	}
+
+	return txs
 }
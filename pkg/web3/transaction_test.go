@@ -0,0 +1,315 @@
+package web3
+
+import (
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestValidateTransactionBatch(t *testing.T) {
+	legacy := func(nonce uint64) *Transaction {
+		return &Transaction{Nonce: nonce, Value: big.NewInt(1), GasPrice: big.NewInt(1)}
+	}
+	dynamic := func(nonce uint64) *Transaction {
+		return &Transaction{Type: DynamicFeeTxType, Nonce: nonce, Value: big.NewInt(1), MaxFeePerGas: big.NewInt(2)}
+	}
+
+	tests := []struct {
+		name    string
+		txs     []*Transaction
+		wantErr string
+	}{
+		{"monotonic", []*Transaction{legacy(1), legacy(2), legacy(5)}, ""},
+		{"dynamic fee without gas price", []*Transaction{dynamic(0), dynamic(1)}, ""},
+		{"duplicate nonce", []*Transaction{legacy(1), legacy(2), legacy(2)}, "reuses nonce 2"},
+		{"decreasing nonce", []*Transaction{legacy(3), legacy(1)}, "lower than previous"},
+		{"legacy without gas price", []*Transaction{{Nonce: 1, Value: big.NewInt(1)}}, "nil gas price"},
+		{"dynamic fee without max fee", []*Transaction{{Type: DynamicFeeTxType, Value: big.NewInt(1), GasPrice: big.NewInt(1)}}, "nil max fee"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTransactionBatch(tt.txs)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestIsZeroAddress(t *testing.T) {
+	tests := []struct {
+		address string
+		want    bool
+	}{
+		{ZeroAddress, true},
+		{"0x0000000000000000000000000000000000000001", false},
+		{"0x000000000000000000000000000000000000dEaD", false},
+		{"0x0", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsZeroAddress(tt.address); got != tt.want {
+			t.Errorf("IsZeroAddress(%q) = %v, want %v", tt.address, got, tt.want)
+		}
+	}
+}
+
+func TestMaxCost(t *testing.T) {
+	tests := []struct {
+		name string
+		tx   *Transaction
+		want int64
+	}{
+		{"legacy", &Transaction{Gas: 21000, GasPrice: big.NewInt(30), Value: big.NewInt(1000)}, 21000*30 + 1000},
+		{"dynamic fee", &Transaction{Type: DynamicFeeTxType, Gas: 21000, GasPrice: big.NewInt(10), MaxFeePerGas: big.NewInt(50), MaxPriorityFeePerGas: big.NewInt(2), Value: big.NewInt(7)}, 21000*50 + 7},
+		{"no value", &Transaction{Gas: 50000, GasPrice: big.NewInt(3)}, 150000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.tx.MaxCost(); got.Int64() != tt.want {
+				t.Errorf("MaxCost() = %s, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateTestTransactions(t *testing.T) {
+	txs := GenerateTestTransactions(5, testOwner, 7, 42)
+	if len(txs) != 5 {
+		t.Fatalf("generated %d transactions, want 5", len(txs))
+	}
+	for i, tx := range txs {
+		if tx.Nonce != 7+uint64(i) {
+			t.Errorf("transaction %d nonce = %d, want %d", i, tx.Nonce, 7+i)
+		}
+	}
+
+	again := GenerateTestTransactions(5, testOwner, 7, 42)
+	other := GenerateTestTransactions(5, testOwner, 7, 43)
+	if again[0].To != txs[0].To || again[0].Value.Cmp(txs[0].Value) != 0 {
+		t.Error("same seed produced different transactions")
+	}
+	if other[0].To == txs[0].To {
+		t.Error("different seeds produced the same recipient")
+	}
+	if err := ValidateTransactionBatch(txs); err != nil {
+		t.Errorf("generated batch is invalid: %v", err)
+	}
+}
+
+func TestTransactionHash(t *testing.T) {
+	// The unsigned transaction from the EIP-155 specification example.
+	tx := &Transaction{
+		Nonce:    9,
+		GasPrice: big.NewInt(20000000000),
+		Gas:      21000,
+		To:       "0x3535353535353535353535353535353535353535",
+		Value:    big.NewInt(1000000000000000000),
+	}
+
+	preimage, _ := hex.DecodeString("e9098504a817c800825208943535353535353535353535353535353535353535880de0b6b3a764000080")
+	hash, err := tx.ComputeHash()
+	if err != nil {
+		t.Fatalf("ComputeHash failed: %v", err)
+	}
+	if want := "0x" + hex.EncodeToString(keccak256(preimage)); hash != want {
+		t.Errorf("ComputeHash() = %s, want %s", hash, want)
+	}
+	if tx.Hash() != hash {
+		t.Errorf("Hash() = %s, want %s", tx.Hash(), hash)
+	}
+
+	signingHash, err := tx.SigningHash(big.NewInt(1))
+	if err != nil {
+		t.Fatalf("SigningHash failed: %v", err)
+	}
+	if got := hex.EncodeToString(signingHash[:]); got != "daf5a779ae972f972197303d7b574746c7ef83eadac0f2791ad23db92e4c8e53" {
+		t.Errorf("EIP-155 signing hash = %s", got)
+	}
+
+	tx.To = "0x1234"
+	if _, err := tx.ComputeHash(); err == nil {
+		t.Error("expected error for invalid recipient")
+	}
+	if got := tx.Hash(); got != "" {
+		t.Errorf("Hash() with invalid recipient = %q, want empty", got)
+	}
+}
+
+func TestTypedTransactionHash(t *testing.T) {
+	to := "0x3535353535353535353535353535353535353535"
+	tests := []struct {
+		name     string
+		tx       *Transaction
+		preimage string
+	}{
+		{
+			name: "access list",
+			tx: &Transaction{
+				Type: AccessListTxType, ChainID: big.NewInt(1), GasPrice: big.NewInt(20000000000),
+				Gas: 21000, To: to, Value: big.NewInt(1000000000000000000),
+			},
+			preimage: "01eb0180" + "8504a817c800" + "825208" + "94" + strings.Repeat("35", 20) + "880de0b6b3a7640000" + "80" + "c0",
+		},
+		{
+			name: "dynamic fee",
+			tx: &Transaction{
+				Type: DynamicFeeTxType, ChainID: big.NewInt(1), MaxPriorityFeePerGas: big.NewInt(1000000000),
+				MaxFeePerGas: big.NewInt(30000000000), Gas: 21000, To: to, Value: big.NewInt(1000000000000000000),
+			},
+			preimage: "02f00180" + "843b9aca00" + "8506fc23ac00" + "825208" + "94" + strings.Repeat("35", 20) + "880de0b6b3a7640000" + "80" + "c0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			preimage, _ := hex.DecodeString(tt.preimage)
+			hash, err := tt.tx.ComputeHash()
+			if err != nil {
+				t.Fatalf("ComputeHash failed: %v", err)
+			}
+			if want := "0x" + hex.EncodeToString(keccak256(preimage)); hash != want {
+				t.Errorf("ComputeHash() = %s, want %s", hash, want)
+			}
+
+			tt.tx.ChainID = nil
+			if _, err := tt.tx.ComputeHash(); err == nil {
+				t.Error("expected error without chain id")
+			}
+		})
+	}
+}
+
+func TestCalculateFeeDynamicFee(t *testing.T) {
+	tx := &Transaction{Type: DynamicFeeTxType, Gas: 21000, MaxFeePerGas: big.NewInt(30), MaxPriorityFeePerGas: big.NewInt(2)}
+	if got := tx.CalculateFee(); got.Int64() != 21000*30 {
+		t.Errorf("CalculateFee() = %s, want %d", got, 21000*30)
+	}
+	if got := tx.CalculateFeeWithBaseFee(big.NewInt(10)); got.Int64() != 21000*12 {
+		t.Errorf("CalculateFeeWithBaseFee(10) = %s, want %d", got, 21000*12)
+	}
+
+	empty := &Transaction{Gas: 21000}
+	if got := empty.EffectiveGasPrice(big.NewInt(10)); got.Sign() != 0 {
+		t.Errorf("EffectiveGasPrice without gas price = %s, want 0", got)
+	}
+}
+
+func TestAccessListEncoding(t *testing.T) {
+	key := "0x" + strings.Repeat("00", 31) + "07"
+	list := AccessList{{Address: testTokenAddress, StorageKeys: []string{key}}}
+
+	items, err := list.rlpItems()
+	if err != nil {
+		t.Fatalf("rlpItems failed: %v", err)
+	}
+	encoded, err := EncodeRLP(items)
+	if err != nil {
+		t.Fatalf("EncodeRLP failed: %v", err)
+	}
+
+	want := "f838" + "f7" + "94" + strings.ToLower(testTokenAddress[2:]) + "e1" + "a0" + key[2:]
+	if got := hex.EncodeToString(encoded); got != want {
+		t.Errorf("access list = %s, want %s", got, want)
+	}
+	if gas := list.Gas(); gas != AccessListAddressGas+AccessListStorageKeyGas {
+		t.Errorf("Gas() = %d, want %d", gas, AccessListAddressGas+AccessListStorageKeyGas)
+	}
+
+	if _, err := (AccessList{{Address: testTokenAddress, StorageKeys: []string{"0x07"}}}).rlpItems(); err == nil {
+		t.Error("expected error for short storage key")
+	}
+}
+
+func TestIntentHashIgnoresGasPrice(t *testing.T) {
+	original := &Transaction{From: testOwner, To: testSpender, Value: big.NewInt(1e18), Gas: 21000, GasPrice: big.NewInt(20), Nonce: 4}
+	bumped := *original
+	bumped.GasPrice = big.NewInt(30)
+	bumped.From = strings.ToLower(testOwner)
+
+	originalHash := original.IntentHash()
+	if bumpedHash := bumped.IntentHash(); originalHash != bumpedHash {
+		t.Error("replacement with a higher gas price changed the intent hash")
+	}
+
+	otherNonce := *original
+	otherNonce.Nonce = 5
+	if otherNonce.IntentHash() == originalHash {
+		t.Error("different nonce produced the same intent hash")
+	}
+
+	otherRecipient := *original
+	otherRecipient.To = testTokenAddress
+	if otherRecipient.IntentHash() == originalHash {
+		t.Error("different recipient produced the same intent hash")
+	}
+
+	unprefixed := *original
+	unprefixed.To = testSpender[2:]
+	if unprefixed.IntentHash() != originalHash {
+		t.Error("recipient without 0x prefix changed the intent hash")
+	}
+}
+
+func TestEstimateGasOfflineDeployment(t *testing.T) {
+	// 5 non-zero and 29 zero bytes: 34 bytes, so two init code words.
+	initCode := "0x6080604052" + strings.Repeat("00", 29)
+
+	gas, err := EstimateGasOffline("", testOwner, initCode, nil)
+	if err != nil {
+		t.Fatalf("EstimateGasOffline failed: %v", err)
+	}
+	want := uint64(21000 + 5*16 + 29*4 + 32000 + 2*2)
+	if gas != want {
+		t.Errorf("deployment gas = %d, want %d", gas, want)
+	}
+
+	call, err := EstimateGasOffline(testTokenAddress, testOwner, initCode, nil)
+	if err != nil {
+		t.Fatalf("EstimateGasOffline failed: %v", err)
+	}
+	if call != 21000+5*16+29*4 {
+		t.Errorf("call gas = %d, want %d", call, 21000+5*16+29*4)
+	}
+
+	transfer, _ := EstimateGasOffline(testTokenAddress, testOwner, "", big.NewInt(1))
+	if transfer != 21000 {
+		t.Errorf("plain transfer gas = %d, want 21000", transfer)
+	}
+}
+
+func TestEstimateGasOfflineMixedCalldata(t *testing.T) {
+	tests := []struct {
+		data string
+		want uint64
+	}{
+		{"0x", 21000},
+		{"0x00", 21004},
+		{"0xff", 21016},
+		{"0x00ff0001", 21000 + 2*4 + 2*16},
+		{"0xa9059cbb" + strings.Repeat("00", 12) + "742d35cc6634c0532925a3b8d82c28d53e01bcf2", 21000 + 4*16 + 12*4 + 20*16},
+	}
+	for _, tt := range tests {
+		gas, err := EstimateGasOffline(testTokenAddress, testOwner, tt.data, nil)
+		if err != nil {
+			t.Fatalf("EstimateGasOffline(%s) failed: %v", tt.data, err)
+		}
+		if gas != tt.want {
+			t.Errorf("EstimateGasOffline(%s) = %d, want %d", tt.data, gas, tt.want)
+		}
+	}
+
+	if _, err := EstimateGasOffline(testTokenAddress, testOwner, "0x0g", nil); err == nil {
+		t.Error("expected error for invalid data")
+	}
+}
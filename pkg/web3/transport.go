@@ -0,0 +1,135 @@
+package web3
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type Transport interface {
+	Do(ctx context.Context, payload []byte) ([]byte, error)
+}
+
+type HTTPTransport struct {
+	URL        string
+	HTTPClient *http.Client
+}
+
+func NewHTTPTransport(url string) *HTTPTransport {
+	return &HTTPTransport{
+		URL:        url,
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (t *HTTPTransport) Do(ctx context.Context, payload []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.URL, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("http request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected http status %d: %s", resp.StatusCode, string(body))
+	}
+
+	return body, nil
+}
+
+type IPCTransport struct {
+	Path string
+
+	mu      sync.Mutex
+	conn    net.Conn
+	decoder *json.Decoder
+}
+
+func NewIPCTransport(path string) *IPCTransport {
+	return &IPCTransport{Path: path}
+}
+
+func (t *IPCTransport) Do(ctx context.Context, payload []byte) ([]byte, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.conn == nil {
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, "unix", t.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to ipc socket: %w", err)
+		}
+		t.conn = conn
+		t.decoder = json.NewDecoder(conn)
+	}
+
+	deadline, hasDeadline := ctx.Deadline()
+	if !hasDeadline {
+		deadline = time.Time{}
+	}
+	if err := t.conn.SetDeadline(deadline); err != nil {
+		t.reset()
+		return nil, fmt.Errorf("failed to set ipc socket deadline: %w", err)
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go func(conn net.Conn) {
+		select {
+		case <-ctx.Done():
+			conn.SetDeadline(time.Now())
+		case <-stop:
+		}
+	}(t.conn)
+
+	if _, err := t.conn.Write(payload); err != nil {
+		t.reset()
+		return nil, fmt.Errorf("failed to write to ipc socket: %w", err)
+	}
+
+	var response json.RawMessage
+	if err := t.decoder.Decode(&response); err != nil {
+		t.reset()
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("failed to read from ipc socket: %w", err)
+	}
+
+	return response, nil
+}
+
+func (t *IPCTransport) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.conn == nil {
+		return nil
+	}
+	err := t.conn.Close()
+	t.conn = nil
+	t.decoder = nil
+	return err
+}
+
+func (t *IPCTransport) reset() {
+	t.conn.Close()
+	t.conn = nil
+	t.decoder = nil
+}
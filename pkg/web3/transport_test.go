@@ -0,0 +1,134 @@
+package web3
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// fakeTransport answers JSON-RPC requests in process so client methods can be
+// tested without a node. handler returns the result value or an RPC error.
+type fakeTransport struct {
+	mu      sync.Mutex
+	handler func(method string, params []json.RawMessage) (interface{}, *RPCError)
+	methods []string
+}
+
+func (f *fakeTransport) Do(ctx context.Context, payload []byte) ([]byte, error) {
+	var req struct {
+		ID     uint64            `json:"id"`
+		Method string            `json:"method"`
+		Params []json.RawMessage `json:"params"`
+	}
+	if err := json.Unmarshal(payload, &req); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	f.methods = append(f.methods, req.Method)
+	f.mu.Unlock()
+
+	result, rpcErr := f.handler(req.Method, req.Params)
+	response := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+	if rpcErr != nil {
+		response["error"] = rpcErr
+	} else {
+		response["result"] = result
+	}
+	return json.Marshal(response)
+}
+
+func newFakeClient(handler func(method string, params []json.RawMessage) (interface{}, *RPCError)) (*Client, *fakeTransport) {
+	transport := &fakeTransport{handler: handler}
+	return NewClientWithTransport(transport), transport
+}
+
+func TestClientUsesPluggableTransport(t *testing.T) {
+	client, transport := newFakeClient(func(method string, params []json.RawMessage) (interface{}, *RPCError) {
+		if method != "web3_clientVersion" {
+			t.Fatalf("unexpected method %s", method)
+		}
+		return "fake/v1", nil
+	})
+
+	result, err := client.Call("web3_clientVersion")
+	if err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+	if string(result) != `"fake/v1"` {
+		t.Errorf("result = %s, want \"fake/v1\"", result)
+	}
+	if len(transport.methods) != 1 {
+		t.Errorf("transport saw %d requests, want 1", len(transport.methods))
+	}
+}
+
+func TestClientReturnsRPCError(t *testing.T) {
+	client, _ := newFakeClient(func(method string, params []json.RawMessage) (interface{}, *RPCError) {
+		return nil, &RPCError{Code: -32601, Message: "method not found"}
+	})
+
+	_, err := client.Call("eth_unknown")
+	rpcErr, ok := err.(*RPCError)
+	if !ok {
+		t.Fatalf("error = %v, want *RPCError", err)
+	}
+	if rpcErr.Code != -32601 {
+		t.Errorf("code = %d, want -32601", rpcErr.Code)
+	}
+}
+
+func TestIPCTransport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "geth.ipc")
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		t.Skipf("unix sockets unavailable: %v", err)
+	}
+	defer listener.Close()
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		decoder := json.NewDecoder(bufio.NewReader(conn))
+		for {
+			var req struct {
+				ID     uint64 `json:"id"`
+				Method string `json:"method"`
+			}
+			if err := decoder.Decode(&req); err != nil {
+				return
+			}
+			response, _ := json.Marshal(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": "0x10"})
+			conn.Write(response)
+		}
+	}()
+
+	transport := NewIPCTransport(path)
+	defer transport.Close()
+	client := NewClientWithTransport(transport)
+
+	for i := 0; i < 2; i++ {
+		number, err := client.BlockNumber()
+		if err != nil {
+			t.Fatalf("BlockNumber over ipc failed: %v", err)
+		}
+		if number.Int64() != 16 {
+			t.Errorf("block number = %s, want 16", number)
+		}
+	}
+}
+
+func TestIPCTransportDialError(t *testing.T) {
+	transport := NewIPCTransport(filepath.Join(t.TempDir(), "missing.ipc"))
+	if _, err := transport.Do(context.Background(), []byte(`{}`)); err == nil {
+		t.Fatal("expected dial error for missing socket")
+	}
+}
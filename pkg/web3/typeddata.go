@@ -0,0 +1,111 @@
+package web3
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+type TypedDataV1Field struct {
+	Type  string
+	Name  string
+	Value interface{}
+}
+
+func HashTypedDataV1(data []TypedDataV1Field) ([32]byte, error) {
+	var digest [32]byte
+	if len(data) == 0 {
+		return digest, fmt.Errorf("typed data must not be empty")
+	}
+
+	var schema []byte
+	var values []byte
+	for i, field := range data {
+		if field.Name == "" {
+			return digest, fmt.Errorf("typed data field %d has no name", i)
+		}
+		schema = append(schema, []byte(field.Type+" "+field.Name)...)
+
+		packed, err := encodePacked(field.Type, field.Value)
+		if err != nil {
+			return digest, fmt.Errorf("failed to encode typed data field %s: %w", field.Name, err)
+		}
+		values = append(values, packed...)
+	}
+
+	copy(digest[:], keccak256(keccak256(schema), keccak256(values)))
+	return digest, nil
+}
+
+func encodePacked(abiType string, value interface{}) ([]byte, error) {
+	switch {
+	case abiType == "string":
+		str, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("string value must be string type")
+		}
+		return []byte(str), nil
+	case abiType == "bytes":
+		switch v := value.(type) {
+		case []byte:
+			return v, nil
+		case string:
+			decoded, err := normalizeHex(v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid hex string")
+			}
+			return decoded, nil
+		default:
+			return nil, fmt.Errorf("bytes value must be []byte or hex string")
+		}
+	case abiType == "address":
+		word, err := encodeAddress(value)
+		if err != nil {
+			return nil, err
+		}
+		return word[12:], nil
+	case abiType == "bool":
+		word, err := encodeBool(value)
+		if err != nil {
+			return nil, err
+		}
+		return word[31:], nil
+	case strings.HasPrefix(abiType, "uint"), strings.HasPrefix(abiType, "int"):
+		return encodePackedInteger(abiType, value)
+	default:
+		return nil, fmt.Errorf("unsupported packed type: %s", abiType)
+	}
+}
+
+func encodePackedInteger(abiType string, value interface{}) ([]byte, error) {
+	signed := strings.HasPrefix(abiType, "int")
+	prefix := "uint"
+	if signed {
+		prefix = "int"
+	}
+	bits, err := integerBits(abiType, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	bigIntValue, err := toBigInt(value)
+	if err != nil {
+		return nil, err
+	}
+	minValue := big.NewInt(0)
+	maxValue := new(big.Int).Lsh(big.NewInt(1), uint(bits))
+	if signed {
+		maxValue.Rsh(maxValue, 1)
+		minValue.Neg(maxValue)
+	}
+	if bigIntValue.Cmp(minValue) < 0 || bigIntValue.Cmp(maxValue) >= 0 {
+		return nil, fmt.Errorf("value %s out of range for %s", bigIntValue.String(), abiType)
+	}
+
+	modulus := new(big.Int).Lsh(big.NewInt(1), uint(bits))
+	encoded := new(big.Int).Mod(bigIntValue, modulus)
+
+	result := make([]byte, bits/8)
+	encoded.FillBytes(result)
+	return result, nil
+}
@@ -0,0 +1,33 @@
+package web3
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestHashTypedDataV1KnownDigest(t *testing.T) {
+	// eth-sig-util typedSignatureHash reference vector.
+	digest, err := HashTypedDataV1([]TypedDataV1Field{
+		{Type: "string", Name: "message", Value: "Hi, Alice!"},
+	})
+	if err != nil {
+		t.Fatalf("HashTypedDataV1 failed: %v", err)
+	}
+
+	want := "14b9f24872e28cc49e72dc104d7380d8e0ba84a3fe2e712704bcac66a5702bd5"
+	if got := hex.EncodeToString(digest[:]); got != want {
+		t.Errorf("digest = %s, want %s", got, want)
+	}
+}
+
+func TestHashTypedDataV1RejectsInvalidFields(t *testing.T) {
+	if _, err := HashTypedDataV1(nil); err == nil {
+		t.Error("expected error for empty typed data")
+	}
+	if _, err := HashTypedDataV1([]TypedDataV1Field{{Type: "uint8", Name: "v", Value: 256}}); err == nil {
+		t.Error("expected error for out-of-range uint8")
+	}
+	if _, err := HashTypedDataV1([]TypedDataV1Field{{Type: "string", Value: "x"}}); err == nil {
+		t.Error("expected error for unnamed field")
+	}
+}
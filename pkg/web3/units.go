@@ -17,13 +17,23 @@ func WeiToEther(wei *big.Int) *big.Float {
 	return ether.Quo(ether, big.NewFloat(WeiPerEther))
 }
 
+// EtherToWei is lossy because float64 cannot represent most decimal
+// amounts exactly. Use EtherToWeiString for exact conversion.
 func EtherToWei(ether float64) *big.Int {
+	if wei, err := EtherToWeiString(strconv.FormatFloat(ether, 'f', -1, 64)); err == nil {
+		return wei
+	}
+
 	etherBig := big.NewFloat(ether)
 	wei := new(big.Float).Mul(etherBig, big.NewFloat(WeiPerEther))
 	result, _ := wei.Int(nil)
 	return result
 }
 
+func EtherToWeiString(ether string) (*big.Int, error) {
+	return ParseUnits(ether, 18)
+}
+
 func WeiToGwei(wei *big.Int) *big.Float {
 	gwei := new(big.Float).SetInt(wei)
 	return gwei.Quo(gwei, big.NewFloat(WeiPerGwei))
@@ -37,38 +47,185 @@ func GweiToWei(gwei float64) *big.Int {
 }
 
 func ParseEther(etherStr string) (*big.Int, error) {
-	ether, err := strconv.ParseFloat(etherStr, 64)
+	wei, err := ParseUnits(etherStr, 18)
 	if err != nil {
 		return nil, fmt.Errorf("invalid ether amount: %w", err)
 	}
-	return EtherToWei(ether), nil
+	return wei, nil
 }
 
 func FormatEther(wei *big.Int, decimals int) string {
-	ether := WeiToEther(wei)
-	format := fmt.Sprintf("%%.%df", decimals)
-	return fmt.Sprintf(format, ether)
+	return formatUnitsFixed(wei, 18, decimals)
 }
 
 func FormatGwei(wei *big.Int, decimals int) string {
-	gwei := WeiToGwei(wei)
-	format := fmt.Sprintf("%%.%df", decimals)
-	return fmt.Sprintf(format, gwei)
+	return formatUnitsFixed(wei, 9, decimals)
+}
+
+// formatUnitsFixed prints exactly displayDecimals fractional digits, rounding
+// half-up (away from zero) on the integer amount so large balances never pick
+// up binary floating point error.
+func formatUnitsFixed(amount *big.Int, unitDecimals, displayDecimals int) string {
+	if displayDecimals < 0 {
+		displayDecimals = 0
+	}
+
+	scaled := new(big.Int).Abs(amount)
+	if displayDecimals < unitDecimals {
+		divisor := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(unitDecimals-displayDecimals)), nil)
+		remainder := new(big.Int)
+		scaled.QuoRem(scaled, divisor, remainder)
+		if remainder.Lsh(remainder, 1).Cmp(divisor) >= 0 {
+			scaled.Add(scaled, big.NewInt(1))
+		}
+	} else {
+		scaled.Mul(scaled, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(displayDecimals-unitDecimals)), nil))
+	}
+
+	digits := scaled.String()
+	if len(digits) <= displayDecimals {
+		digits = strings.Repeat("0", displayDecimals-len(digits)+1) + digits
+	}
+
+	result := digits
+	if displayDecimals > 0 {
+		split := len(digits) - displayDecimals
+		result = digits[:split] + "." + digits[split:]
+	}
+	if amount.Sign() < 0 && scaled.Sign() != 0 {
+		result = "-" + result
+	}
+	return result
+}
+
+var Denomination = map[string]int{
+	"wei":      0,
+	"kwei":     3,
+	"babbage":  3,
+	"mwei":     6,
+	"lovelace": 6,
+	"gwei":     9,
+	"shannon":  9,
+	"szabo":    12,
+	"finney":   15,
+	"ether":    18,
+}
+
+func denominationDecimals(unit string) (int, error) {
+	decimals, ok := Denomination[strings.ToLower(unit)]
+	if !ok {
+		return 0, fmt.Errorf("unknown unit %q", unit)
+	}
+	return decimals, nil
+}
+
+func FromWei(wei *big.Int, unit string) (*big.Float, error) {
+	if wei == nil {
+		return nil, fmt.Errorf("wei amount is required")
+	}
+	decimals, err := denominationDecimals(unit)
+	if err != nil {
+		return nil, err
+	}
+
+	prec := uint(wei.BitLen()) + 128
+	value := new(big.Float).SetPrec(prec).SetInt(wei)
+	divisor := new(big.Float).SetPrec(prec).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil))
+	return value.Quo(value, divisor), nil
+}
+
+func ToWei(amount string, unit string) (*big.Int, error) {
+	decimals, err := denominationDecimals(unit)
+	if err != nil {
+		return nil, err
+	}
+
+	wei, err := ParseUnitsExact(strings.TrimSpace(amount), decimals)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s amount %q: %w", unit, amount, err)
+	}
+	return wei, nil
+}
+
+// ParseValueWithUnit accepts amounts such as "20 gwei", "1.5ether" or "100"
+// (wei). Fractions that do not fit in wei are rejected rather than truncated.
+func ParseValueWithUnit(s string) (*big.Int, error) {
+	value := strings.TrimSpace(s)
+	split := strings.IndexFunc(value, func(r rune) bool {
+		return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+	})
+
+	unit := "wei"
+	if split >= 0 {
+		unit = value[split:]
+		value = value[:split]
+	}
+
+	return ToWei(value, unit)
 }
 
+// ParseUnits silently truncates fractional digits beyond decimals.
+// Use ParseUnitsExact to reject amounts that would lose precision.
 func ParseUnits(amount string, decimals int) (*big.Int, error) {
-	parts := strings.Split(amount, ".")
+	return parseUnits(amount, decimals, truncateUnits)
+}
+
+func ParseUnitsExact(amount string, decimals int) (*big.Int, error) {
+	return parseUnits(amount, decimals, exactUnits)
+}
+
+// ParseUnitsRounded rounds excess fractional digits half-up (away from zero)
+// when round is true, and returns an error on any precision loss otherwise.
+func ParseUnitsRounded(amount string, decimals int, round bool) (*big.Int, error) {
+	if round {
+		return parseUnits(amount, decimals, roundUnits)
+	}
+	return parseUnits(amount, decimals, exactUnits)
+}
+
+type unitsRounding int
+
+const (
+	truncateUnits unitsRounding = iota
+	exactUnits
+	roundUnits
+)
+
+func parseUnits(amount string, decimals int, rounding unitsRounding) (*big.Int, error) {
+	unsigned := amount
+	negative := false
+	if strings.HasPrefix(unsigned, "-") {
+		negative = true
+		unsigned = unsigned[1:]
+	} else if strings.HasPrefix(unsigned, "+") {
+		unsigned = unsigned[1:]
+	}
+
+	parts := strings.Split(unsigned, ".")
 	if len(parts) > 2 {
 		return nil, fmt.Errorf("invalid amount format")
 	}
 
 	integerPart := parts[0]
+	if !isDecimalDigits(integerPart) {
+		return nil, fmt.Errorf("invalid integer part in amount %q", amount)
+	}
+
 	fractionalPart := ""
 	if len(parts) == 2 {
 		fractionalPart = parts[1]
+		if !isDecimalDigits(fractionalPart) {
+			return nil, fmt.Errorf("invalid fractional part in amount %q", amount)
+		}
 	}
 
+	roundUp := false
 	if len(fractionalPart) > decimals {
+		dropped := fractionalPart[decimals:]
+		if rounding == exactUnits && strings.TrimRight(dropped, "0") != "" {
+			return nil, fmt.Errorf("amount %s exceeds %d decimals", amount, decimals)
+		}
+		roundUp = rounding == roundUnits && dropped[0] >= '5'
 		fractionalPart = fractionalPart[:decimals]
 	}
 
@@ -82,10 +239,32 @@ func ParseUnits(amount string, decimals int) (*big.Int, error) {
 		return nil, fmt.Errorf("failed to parse amount")
 	}
 
+	if roundUp {
+		result.Add(result, big.NewInt(1))
+	}
+	if negative {
+		result.Neg(result)
+	}
 	return result, nil
 }
 
+func isDecimalDigits(value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, c := range value {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
 func FormatUnits(amount *big.Int, decimals int) string {
+	if amount.Sign() < 0 {
+		return "-" + FormatUnits(new(big.Int).Neg(amount), decimals)
+	}
+
 	divisor := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
 
 	integerPart := new(big.Int).Div(amount, divisor)
@@ -107,3 +286,51 @@ func FormatUnits(amount *big.Int, decimals int) string {
 
 	return integerPart.String() + "." + fractionalStr
 }
+
+// FormatUnitsWithGrouping shows at most displayDecimals fractional digits
+// (truncated, trailing zeros trimmed); a negative displayDecimals shows all.
+func FormatUnitsWithGrouping(amount *big.Int, decimals, displayDecimals int, sep rune) string {
+	formatted := FormatUnits(new(big.Int).Abs(amount), decimals)
+
+	integerPart, fractionalPart, _ := strings.Cut(formatted, ".")
+	if displayDecimals >= 0 && len(fractionalPart) > displayDecimals {
+		fractionalPart = strings.TrimRight(fractionalPart[:displayDecimals], "0")
+	}
+
+	var grouped strings.Builder
+	for i, digit := range integerPart {
+		if i > 0 && (len(integerPart)-i)%3 == 0 {
+			grouped.WriteRune(sep)
+		}
+		grouped.WriteRune(digit)
+	}
+
+	result := grouped.String()
+	if fractionalPart != "" {
+		result += "." + fractionalPart
+	}
+	if amount.Sign() < 0 && (integerPart != "0" || fractionalPart != "") {
+		result = "-" + result
+	}
+	return result
+}
+
+// FormatEtherWithUSD treats a nil wei amount as zero.
+func FormatEtherWithUSD(wei *big.Int, usdPerEth *big.Rat, ethDecimals, usdDecimals int) (ethStr, usdStr string) {
+	if wei == nil {
+		wei = new(big.Int)
+	}
+	ether := new(big.Rat).SetFrac(wei, big.NewInt(WeiPerEther))
+
+	ethStr = ether.FloatString(ethDecimals)
+	if strings.Contains(ethStr, ".") {
+		ethStr = strings.TrimRight(strings.TrimRight(ethStr, "0"), ".")
+	}
+
+	if usdPerEth == nil {
+		return ethStr, ""
+	}
+
+	usd := new(big.Rat).Mul(ether, usdPerEth)
+	return ethStr, usd.FloatString(usdDecimals)
+}
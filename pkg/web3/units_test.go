@@ -0,0 +1,306 @@
+package web3
+
+import (
+	"math/big"
+	"testing"
+)
+
+func mustBig(t *testing.T, value string) *big.Int {
+	t.Helper()
+	result, ok := new(big.Int).SetString(value, 10)
+	if !ok {
+		t.Fatalf("invalid test integer %q", value)
+	}
+	return result
+}
+
+func TestParseUnitsOverPrecise(t *testing.T) {
+	truncated, err := ParseUnits("1.2345678", 6)
+	if err != nil {
+		t.Fatalf("ParseUnits failed: %v", err)
+	}
+	if truncated.Cmp(big.NewInt(1234567)) != 0 {
+		t.Errorf("ParseUnits = %s, want 1234567", truncated)
+	}
+
+	if _, err := ParseUnitsExact("1.2345678", 6); err == nil {
+		t.Error("ParseUnitsExact accepted an amount with 7 decimals for 6")
+	}
+
+	exact, err := ParseUnitsExact("1.2345670", 6)
+	if err != nil {
+		t.Fatalf("ParseUnitsExact rejected trailing zeros: %v", err)
+	}
+	if exact.Cmp(big.NewInt(1234567)) != 0 {
+		t.Errorf("ParseUnitsExact = %s, want 1234567", exact)
+	}
+}
+
+func TestFormatEtherWithUSD(t *testing.T) {
+	price := new(big.Rat).SetFrac64(400_050, 200) // 2000.25 USD
+	tests := []struct {
+		name    string
+		wei     *big.Int
+		price   *big.Rat
+		wantEth string
+		wantUSD string
+	}{
+		{"one and a half ether", mustBig(t, "1500000000000000000"), price, "1.5", "3000.38"},
+		{"whole ether", mustBig(t, "2000000000000000000"), price, "2", "4000.50"},
+		{"no price", mustBig(t, "1000000000000000000"), nil, "1", ""},
+		{"nil wei", nil, price, "0", "0.00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eth, usd := FormatEtherWithUSD(tt.wei, tt.price, 4, 2)
+			if eth != tt.wantEth || usd != tt.wantUSD {
+				t.Errorf("FormatEtherWithUSD = (%q, %q), want (%q, %q)", eth, usd, tt.wantEth, tt.wantUSD)
+			}
+		})
+	}
+}
+
+func TestParseEther(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"0.000000000000000001", "1"},
+		{"0.1", "100000000000000000"},
+		{"123456789012.345678901234567890", "123456789012345678901234567890"},
+		{"1", "1000000000000000000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			wei, err := ParseEther(tt.input)
+			if err != nil {
+				t.Fatalf("ParseEther failed: %v", err)
+			}
+			if wei.Cmp(mustBig(t, tt.want)) != 0 {
+				t.Errorf("ParseEther(%s) = %s, want %s", tt.input, wei, tt.want)
+			}
+		})
+	}
+
+	if _, err := ParseEther("1e18"); err == nil {
+		t.Error("ParseEther accepted exponent notation")
+	}
+}
+
+func TestParseUnitsSigns(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"-0.5", "-500000000000000000"},
+		{"+1.0", "1000000000000000000"},
+		{"-1.5", "-1500000000000000000"},
+		{"0", "0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseUnits(tt.input, 18)
+			if err != nil {
+				t.Fatalf("ParseUnits failed: %v", err)
+			}
+			if got.Cmp(mustBig(t, tt.want)) != 0 {
+				t.Errorf("ParseUnits(%q) = %s, want %s", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseUnitsMalformed(t *testing.T) {
+	for _, input := range []string{"1.2.3", "abc", "", "-", "+-1", "--1", ".5", "1.", " 1", "1 ", "1.2a", "0x10"} {
+		if got, err := ParseUnits(input, 18); err == nil {
+			t.Errorf("ParseUnits(%q) = %s, want error", input, got)
+		}
+	}
+}
+
+func TestParseUnitsRounded(t *testing.T) {
+	tests := []struct {
+		input   string
+		round   bool
+		want    int64
+		wantErr bool
+	}{
+		{"1.9999999", true, 200, false},
+		{"1.994", true, 199, false},
+		{"1.995", true, 200, false},
+		{"-1.995", true, -200, false},
+		{"1.9999999", false, 0, true},
+		{"1.990", false, 199, false},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseUnitsRounded(tt.input, 2, tt.round)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseUnitsRounded(%q, round=%v) = %s, want error", tt.input, tt.round, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseUnitsRounded(%q, round=%v) failed: %v", tt.input, tt.round, err)
+			continue
+		}
+		if got.Int64() != tt.want {
+			t.Errorf("ParseUnitsRounded(%q, round=%v) = %s, want %d", tt.input, tt.round, got, tt.want)
+		}
+	}
+
+	if truncated, _ := ParseUnits("1.9999999", 2); truncated.Int64() != 199 {
+		t.Errorf("ParseUnits default = %s, want truncation to 199", truncated)
+	}
+}
+
+func TestFormatUnitsWithGrouping(t *testing.T) {
+	tests := []struct {
+		name            string
+		amount          *big.Int
+		decimals        int
+		displayDecimals int
+		want            string
+	}{
+		{"integer", big.NewInt(1234567), 0, 2, "1,234,567"},
+		{"fractional remainder", mustBig(t, "123456789123000000000000000"), 18, 3, "123,456,789.123"},
+		{"below one", big.NewInt(5), 2, 2, "0.05"},
+		{"negative", big.NewInt(-1234567500), 3, 2, "-1,234,567.5"},
+		{"three digits", big.NewInt(999), 0, 0, "999"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatUnitsWithGrouping(tt.amount, tt.decimals, tt.displayDecimals, ','); got != tt.want {
+				t.Errorf("FormatUnitsWithGrouping = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatUnitsNegative(t *testing.T) {
+	tests := []struct {
+		amount   *big.Int
+		decimals int
+		want     string
+	}{
+		{big.NewInt(-1500000), 6, "-1.5"},
+		{mustBig(t, "-999999999999999999"), 18, "-0.999999999999999999"},
+		{big.NewInt(-2000000), 6, "-2"},
+		{big.NewInt(-1), 6, "-0.000001"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatUnits(tt.amount, tt.decimals); got != tt.want {
+			t.Errorf("FormatUnits(%s, %d) = %s, want %s", tt.amount, tt.decimals, got, tt.want)
+		}
+	}
+}
+
+func TestParseValueWithUnit(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"100", "100"},
+		{"100 wei", "100"},
+		{"1 kwei", "1000"},
+		{"1 mwei", "1000000"},
+		{"20 gwei", "20000000000"},
+		{"1gwei", "1000000000"},
+		{"1 GWEI", "1000000000"},
+		{"1 szabo", "1000000000000"},
+		{"1 finney", "1000000000000000"},
+		{"1.5 ether", "1500000000000000000"},
+		{"  2Ether ", "2000000000000000000"},
+	}
+	for _, tt := range tests {
+		got, err := ParseValueWithUnit(tt.input)
+		if err != nil {
+			t.Fatalf("ParseValueWithUnit(%q) failed: %v", tt.input, err)
+		}
+		if got.String() != tt.want {
+			t.Errorf("ParseValueWithUnit(%q) = %s, want %s", tt.input, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"", "gwei", "1 bitcoin", "1.5 wei", "1.2.3 ether", "0.0000000001 gwei"} {
+		if _, err := ParseValueWithUnit(bad); err == nil {
+			t.Errorf("ParseValueWithUnit(%q) should fail", bad)
+		}
+	}
+}
+
+func TestEtherFinneyConversion(t *testing.T) {
+	wei, err := ToWei("1", "ether")
+	if err != nil {
+		t.Fatalf("ToWei failed: %v", err)
+	}
+	finney, err := FromWei(wei, "finney")
+	if err != nil {
+		t.Fatalf("FromWei failed: %v", err)
+	}
+	if finney.Text('f', 0) != "1000" {
+		t.Errorf("1 ether = %s finney, want 1000", finney.Text('f', 0))
+	}
+
+	back, err := ToWei(finney.Text('f', 0), "finney")
+	if err != nil {
+		t.Fatalf("ToWei failed: %v", err)
+	}
+	if back.Cmp(wei) != 0 {
+		t.Errorf("1000 finney = %s wei, want %s", back, wei)
+	}
+
+	for name, decimals := range Denomination {
+		one, err := ToWei("1", name)
+		if err != nil {
+			t.Fatalf("ToWei(1, %s) failed: %v", name, err)
+		}
+		if want := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil); one.Cmp(want) != 0 {
+			t.Errorf("1 %s = %s wei, want %s", name, one, want)
+		}
+	}
+
+	if _, err := FromWei(wei, "bitcoin"); err == nil {
+		t.Error("expected error for an unknown unit")
+	}
+	if _, err := FromWei(nil, "ether"); err == nil {
+		t.Error("expected error for a nil amount")
+	}
+}
+
+func TestFormatEtherLargeBalancePrecision(t *testing.T) {
+	// 20 significant digits: a float64 path loses the last four.
+	wei := mustBig(t, "25123456789012345678")
+	if got := FormatEther(wei, 18); got != "25.123456789012345678" {
+		t.Errorf("FormatEther(18) = %s, want 25.123456789012345678", got)
+	}
+
+	tests := []struct {
+		wei      string
+		decimals int
+		want     string
+	}{
+		{"25123456789012345678", 17, "25.12345678901234568"},
+		{"25123456789012345678", 4, "25.1235"},
+		{"25999999500000000000", 6, "26.000000"},
+		{"25999999499999999999", 6, "25.999999"},
+		{"-25999999500000000000", 6, "-26.000000"},
+		{"123456789012345678901234567", 2, "123456789.01"},
+		{"25000000000000000000", 0, "25"},
+	}
+	for _, tt := range tests {
+		if got := FormatEther(mustBig(t, tt.wei), tt.decimals); got != tt.want {
+			t.Errorf("FormatEther(%s, %d) = %s, want %s", tt.wei, tt.decimals, got, tt.want)
+		}
+	}
+
+	if got := FormatGwei(mustBig(t, "25000001500000"), 3); got != "25000.002" {
+		t.Errorf("FormatGwei = %s, want 25000.002", got)
+	}
+}
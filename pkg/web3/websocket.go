@@ -0,0 +1,255 @@
+package web3
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+const (
+	wsOpContinuation = 0x0
+	wsOpText         = 0x1
+	wsOpBinary       = 0x2
+	wsOpClose        = 0x8
+	wsOpPing         = 0x9
+	wsOpPong         = 0xa
+
+	wsAcceptGUID      = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+	wsMaxMessageBytes = 64 << 20
+)
+
+type wsConn struct {
+	conn    net.Conn
+	reader  *bufio.Reader
+	mask    bool
+	writeMu sync.Mutex
+}
+
+func dialWebSocket(ctx context.Context, rawURL string) (*wsConn, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid websocket url: %w", err)
+	}
+
+	host := u.Host
+	var dialer net.Dialer
+	var conn net.Conn
+	switch u.Scheme {
+	case "ws":
+		if u.Port() == "" {
+			host = net.JoinHostPort(u.Hostname(), "80")
+		}
+		conn, err = dialer.DialContext(ctx, "tcp", host)
+	case "wss":
+		if u.Port() == "" {
+			host = net.JoinHostPort(u.Hostname(), "443")
+		}
+		tlsDialer := tls.Dialer{NetDialer: &dialer, Config: &tls.Config{ServerName: u.Hostname()}}
+		conn, err = tlsDialer.DialContext(ctx, "tcp", host)
+	default:
+		return nil, fmt.Errorf("unsupported websocket scheme %q", u.Scheme)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to websocket: %w", err)
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+		defer conn.SetDeadline(time.Time{})
+	}
+
+	keyBytes := make([]byte, 16)
+	if _, err := rand.Read(keyBytes); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to generate websocket key: %w", err)
+	}
+	key := base64.StdEncoding.EncodeToString(keyBytes)
+
+	req := &http.Request{
+		Method:     http.MethodGet,
+		URL:        u,
+		Host:       u.Host,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header: http.Header{
+			"Upgrade":               {"websocket"},
+			"Connection":            {"Upgrade"},
+			"Sec-WebSocket-Key":     {key},
+			"Sec-WebSocket-Version": {"13"},
+		},
+	}
+	if err := req.Write(conn); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to send websocket handshake: %w", err)
+	}
+
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, req)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to read websocket handshake: %w", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		conn.Close()
+		return nil, fmt.Errorf("websocket handshake failed with status %d", resp.StatusCode)
+	}
+	if resp.Header.Get("Sec-WebSocket-Accept") != websocketAccept(key) {
+		conn.Close()
+		return nil, fmt.Errorf("websocket handshake returned an invalid accept key")
+	}
+
+	return &wsConn{conn: conn, reader: reader, mask: true}, nil
+}
+
+func websocketAccept(key string) string {
+	sum := sha1.Sum([]byte(key + wsAcceptGUID))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+func (c *wsConn) writeMessage(payload []byte) error {
+	return c.writeFrame(wsOpText, payload)
+}
+
+func (c *wsConn) writeFrame(opcode byte, payload []byte) error {
+	header := []byte{0x80 | opcode}
+
+	maskBit := byte(0)
+	if c.mask {
+		maskBit = 0x80
+	}
+
+	switch length := len(payload); {
+	case length < 126:
+		header = append(header, maskBit|byte(length))
+	case length <= 0xffff:
+		header = append(header, maskBit|126, 0, 0)
+		binary.BigEndian.PutUint16(header[2:], uint16(length))
+	default:
+		header = append(header, maskBit|127, 0, 0, 0, 0, 0, 0, 0, 0)
+		binary.BigEndian.PutUint64(header[2:], uint64(length))
+	}
+
+	body := payload
+	if c.mask {
+		maskKey := make([]byte, 4)
+		if _, err := rand.Read(maskKey); err != nil {
+			return fmt.Errorf("failed to generate websocket mask: %w", err)
+		}
+		header = append(header, maskKey...)
+
+		body = make([]byte, len(payload))
+		for i, b := range payload {
+			body[i] = b ^ maskKey[i%4]
+		}
+	}
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	if _, err := c.conn.Write(append(header, body...)); err != nil {
+		return fmt.Errorf("failed to write websocket frame: %w", err)
+	}
+	return nil
+}
+
+func (c *wsConn) readMessage() ([]byte, error) {
+	var message []byte
+	for {
+		fin, opcode, payload, err := c.readFrame()
+		if err != nil {
+			return nil, err
+		}
+
+		switch opcode {
+		case wsOpPing:
+			if err := c.writeFrame(wsOpPong, payload); err != nil {
+				return nil, err
+			}
+			continue
+		case wsOpPong:
+			continue
+		case wsOpClose:
+			c.writeFrame(wsOpClose, nil)
+			return nil, io.EOF
+		case wsOpText, wsOpBinary, wsOpContinuation:
+		default:
+			return nil, fmt.Errorf("unsupported websocket opcode %d", opcode)
+		}
+
+		message = append(message, payload...)
+		if len(message) > wsMaxMessageBytes {
+			return nil, fmt.Errorf("websocket message exceeds %d bytes", wsMaxMessageBytes)
+		}
+		if fin {
+			return message, nil
+		}
+	}
+}
+
+func (c *wsConn) readFrame() (bool, byte, []byte, error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(c.reader, header); err != nil {
+		return false, 0, nil, err
+	}
+
+	fin := header[0]&0x80 != 0
+	opcode := header[0] & 0x0f
+	masked := header[1]&0x80 != 0
+	length := uint64(header[1] & 0x7f)
+
+	switch length {
+	case 126:
+		extended := make([]byte, 2)
+		if _, err := io.ReadFull(c.reader, extended); err != nil {
+			return false, 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(extended))
+	case 127:
+		extended := make([]byte, 8)
+		if _, err := io.ReadFull(c.reader, extended); err != nil {
+			return false, 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(extended)
+	}
+	if length > wsMaxMessageBytes {
+		return false, 0, nil, fmt.Errorf("websocket frame exceeds %d bytes", wsMaxMessageBytes)
+	}
+
+	var maskKey []byte
+	if masked {
+		maskKey = make([]byte, 4)
+		if _, err := io.ReadFull(c.reader, maskKey); err != nil {
+			return false, 0, nil, err
+		}
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(c.reader, payload); err != nil {
+		return false, 0, nil, err
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= maskKey[i%4]
+		}
+	}
+
+	return fin, opcode, payload, nil
+}
+
+func (c *wsConn) Close() error {
+	c.writeFrame(wsOpClose, nil)
+	return c.conn.Close()
+}
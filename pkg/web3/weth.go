@@ -0,0 +1,97 @@
+package web3
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+const (
+	WETH_DEPOSIT_SELECTOR  = "d0e30db0"
+	WETH_WITHDRAW_SELECTOR = "2e1a7d4d"
+)
+
+var (
+	WETH_DEPOSIT_SIGNATURE    = CreateEventSignature("Deposit", []string{"address", "uint256"})
+	WETH_WITHDRAWAL_SIGNATURE = CreateEventSignature("Withdrawal", []string{"address", "uint256"})
+)
+
+type WETHToken struct {
+	*ERC20Token
+}
+
+func NewWETHToken(address string) *WETHToken {
+	return &WETHToken{
+		ERC20Token: NewERC20Token(address, "Wrapped Ether", "WETH", 18),
+	}
+}
+
+type DepositEvent struct {
+	Dst    string
+	Amount *big.Int
+}
+
+type WithdrawalEvent struct {
+	Src    string
+	Amount *big.Int
+}
+
+func (token *WETHToken) EncodeDeposit() []byte {
+	selector, _ := hex.DecodeString(WETH_DEPOSIT_SELECTOR)
+	return selector
+}
+
+func (token *WETHToken) EncodeWithdraw(amount *big.Int) ([]byte, error) {
+	if amount == nil || amount.Sign() < 0 {
+		return nil, fmt.Errorf("withdraw amount must be non-negative")
+	}
+	if amount.BitLen() > 256 {
+		return nil, fmt.Errorf("withdraw amount exceeds uint256")
+	}
+
+	selector, _ := hex.DecodeString(WETH_WITHDRAW_SELECTOR)
+	amountBytes := make([]byte, 32)
+	amount.FillBytes(amountBytes)
+
+	return append(selector, amountBytes...), nil
+}
+
+func (token *WETHToken) DecodeDepositEvent(log Event) (*DepositEvent, error) {
+	dst, amount, err := decodeWETHEvent(log, WETH_DEPOSIT_SIGNATURE, "deposit")
+	if err != nil {
+		return nil, err
+	}
+	return &DepositEvent{Dst: dst, Amount: amount}, nil
+}
+
+func (token *WETHToken) DecodeWithdrawalEvent(log Event) (*WithdrawalEvent, error) {
+	src, amount, err := decodeWETHEvent(log, WETH_WITHDRAWAL_SIGNATURE, "withdrawal")
+	if err != nil {
+		return nil, err
+	}
+	return &WithdrawalEvent{Src: src, Amount: amount}, nil
+}
+
+func decodeWETHEvent(log Event, signature, name string) (string, *big.Int, error) {
+	if len(log.Topics) != 2 {
+		return "", nil, fmt.Errorf("%s event must have 2 topics, got %d", name, len(log.Topics))
+	}
+	if !strings.EqualFold(log.Topics[0], signature) {
+		return "", nil, fmt.Errorf("topic %s is not a %s event", log.Topics[0], name)
+	}
+	if len(log.Topics[1]) != 66 {
+		return "", nil, fmt.Errorf("invalid indexed address topic")
+	}
+
+	data, err := normalizeHex(log.Data)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid %s data: %w", name, err)
+	}
+	if len(data) != 32 {
+		return "", nil, fmt.Errorf("%s event data must be 32 bytes", name)
+	}
+	amount := new(big.Int).SetBytes(data)
+
+	return "0x" + log.Topics[1][26:], amount, nil
+}
@@ -0,0 +1,64 @@
+package web3
+
+import (
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+const testWETHAddress = "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2"
+
+func TestDecodeWETHEvents(t *testing.T) {
+	token := NewWETHToken(testWETHAddress)
+
+	deposit, err := token.DecodeDepositEvent(Event{
+		Address: testWETHAddress,
+		Topics:  []string{WETH_DEPOSIT_SIGNATURE, testTopicFrom},
+		Data:    "0x0000000000000000000000000000000000000000000000000de0b6b3a7640000",
+	})
+	if err != nil {
+		t.Fatalf("DecodeDepositEvent failed: %v", err)
+	}
+	if !strings.EqualFold(deposit.Dst, testOwner) {
+		t.Errorf("dst = %s, want %s", deposit.Dst, testOwner)
+	}
+	if deposit.Amount.Cmp(big.NewInt(1e18)) != 0 {
+		t.Errorf("deposit amount = %s, want 1e18", deposit.Amount)
+	}
+
+	withdrawal, err := token.DecodeWithdrawalEvent(Event{
+		Address: testWETHAddress,
+		Topics:  []string{WETH_WITHDRAWAL_SIGNATURE, testTopicTo},
+		Data:    testAmount1M,
+	})
+	if err != nil {
+		t.Fatalf("DecodeWithdrawalEvent failed: %v", err)
+	}
+	if !strings.EqualFold(withdrawal.Src, testSpender) {
+		t.Errorf("src = %s, want %s", withdrawal.Src, testSpender)
+	}
+	if withdrawal.Amount.Int64() != 1_000_000 {
+		t.Errorf("withdrawal amount = %s, want 1000000", withdrawal.Amount)
+	}
+
+	if _, err := token.DecodeWithdrawalEvent(Event{Topics: []string{WETH_DEPOSIT_SIGNATURE, testTopicFrom}, Data: testAmount1M}); err == nil {
+		t.Error("expected error decoding a deposit as a withdrawal")
+	}
+}
+
+func TestEncodeWithdraw(t *testing.T) {
+	token := NewWETHToken(testWETHAddress)
+
+	data, err := token.EncodeWithdraw(big.NewInt(1e18))
+	if err != nil {
+		t.Fatalf("EncodeWithdraw failed: %v", err)
+	}
+	if got := hex.EncodeToString(data); got != WETH_WITHDRAW_SELECTOR+"0000000000000000000000000000000000000000000000000de0b6b3a7640000" {
+		t.Errorf("calldata = %s", got)
+	}
+
+	if _, err := token.EncodeWithdraw(new(big.Int).Lsh(big.NewInt(1), 256)); err == nil {
+		t.Error("expected error for amount wider than 256 bits")
+	}
+}
@@ -0,0 +1,448 @@
+package web3
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	DefaultReconnectDelay = time.Second
+	MaxReconnectDelay     = 30 * time.Second
+)
+
+type WSClient struct {
+	url            string
+	ReconnectDelay time.Duration
+
+	requestID uint64
+
+	mu      sync.Mutex
+	conn    *wsConn
+	pending map[uint64]*wsPending
+	subs    map[string]*wsSubscription
+	retry   []*wsSubscription
+	closed  bool
+	done    chan struct{}
+	errors  chan error
+}
+
+type wsPending struct {
+	results      chan wsResult
+	subscription *wsSubscription
+}
+
+type wsResult struct {
+	response rpcResponse
+	err      error
+}
+
+// wsSubscription tracks the id the node currently uses for a subscription.
+// The public EventSubscription.ID keeps the id from SubscribeLogs so callers
+// can read it without locking; nodeID changes on every resubscribe.
+type wsSubscription struct {
+	nodeID       string
+	params       map[string]interface{}
+	subscription *EventSubscription
+	stopped      bool
+}
+
+type wsMessage struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	Result json.RawMessage `json:"result"`
+	Error  *RPCError       `json:"error"`
+}
+
+func NewWSClient(ctx context.Context, rawURL string) (*WSClient, error) {
+	conn, err := dialWebSocket(ctx, rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &WSClient{
+		url:            rawURL,
+		ReconnectDelay: DefaultReconnectDelay,
+		conn:           conn,
+		pending:        make(map[uint64]*wsPending),
+		subs:           make(map[string]*wsSubscription),
+		done:           make(chan struct{}),
+		errors:         make(chan error, 100),
+	}
+	go client.readLoop(conn)
+	return client, nil
+}
+
+func (c *WSClient) Errors() <-chan error {
+	return c.errors
+}
+
+func (c *WSClient) CallContext(ctx context.Context, method string, params ...interface{}) (json.RawMessage, error) {
+	return c.call(ctx, nil, method, params...)
+}
+
+// call registers subscription, when given, from the read loop as soon as the
+// node answers, so notifications sent right after the response are not lost.
+func (c *WSClient) call(ctx context.Context, subscription *wsSubscription, method string, params ...interface{}) (json.RawMessage, error) {
+	if params == nil {
+		params = []interface{}{}
+	}
+
+	id := atomic.AddUint64(&c.requestID, 1)
+	payload, err := json.Marshal(rpcRequest{
+		JSONRPC: "2.0",
+		ID:      id,
+		Method:  method,
+		Params:  params,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	results := make(chan wsResult, 1)
+	c.mu.Lock()
+	conn := c.conn
+	if conn == nil {
+		c.mu.Unlock()
+		return nil, fmt.Errorf("websocket is not connected")
+	}
+	c.pending[id] = &wsPending{results: results, subscription: subscription}
+	c.mu.Unlock()
+
+	if err := conn.writeMessage(payload); err != nil {
+		c.removePending(id)
+		return nil, err
+	}
+
+	select {
+	case result := <-results:
+		if result.err != nil {
+			return nil, result.err
+		}
+		if result.response.Error != nil {
+			return nil, result.response.Error
+		}
+		return result.response.Result, nil
+	case <-ctx.Done():
+		c.removePending(id)
+		return nil, ctx.Err()
+	}
+}
+
+func (c *WSClient) SubscribeLogs(filter *EventFilter) (*EventSubscription, error) {
+	if filter != nil {
+		if err := filter.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	record := &wsSubscription{
+		params:       logFilterParams(filter),
+		subscription: RestoreSubscription("", filter),
+	}
+	record.subscription.unsubscribe = func() {
+		c.unsubscribe(record)
+	}
+
+	nodeID, err := c.subscribe(context.Background(), record)
+	if err != nil {
+		return nil, err
+	}
+	record.subscription.ID = nodeID
+
+	return record.subscription, nil
+}
+
+func (c *WSClient) Close() error {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil
+	}
+	c.closed = true
+	close(c.done)
+	conn := c.conn
+	c.conn = nil
+	c.failPending(fmt.Errorf("websocket client closed"))
+	c.mu.Unlock()
+
+	if conn != nil {
+		return conn.Close()
+	}
+	return nil
+}
+
+func (c *WSClient) subscribe(ctx context.Context, record *wsSubscription) (string, error) {
+	result, err := c.call(ctx, record, "eth_subscribe", "logs", record.params)
+	if err != nil {
+		return "", err
+	}
+
+	var nodeID string
+	if err := json.Unmarshal(result, &nodeID); err != nil {
+		return "", fmt.Errorf("invalid subscription id: %w", err)
+	}
+	return nodeID, nil
+}
+
+func (c *WSClient) unsubscribe(record *wsSubscription) {
+	c.mu.Lock()
+	record.stopped = true
+	nodeID := record.nodeID
+	delete(c.subs, nodeID)
+	for i, pending := range c.retry {
+		if pending == record {
+			c.retry = append(c.retry[:i], c.retry[i+1:]...)
+			break
+		}
+	}
+	connected := c.conn != nil
+	c.mu.Unlock()
+
+	if connected {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		c.unsubscribeNode(ctx, nodeID)
+	}
+}
+
+func (c *WSClient) unsubscribeNode(ctx context.Context, nodeID string) {
+	if _, err := c.CallContext(ctx, "eth_unsubscribe", nodeID); err != nil {
+		c.reportError(fmt.Errorf("failed to unsubscribe %s: %w", nodeID, err))
+	}
+}
+
+func (c *WSClient) readLoop(conn *wsConn) {
+	for {
+		payload, err := conn.readMessage()
+		if err != nil {
+			c.handleDisconnect(conn, err)
+			return
+		}
+		c.dispatch(payload)
+	}
+}
+
+func (c *WSClient) dispatch(payload []byte) {
+	var msg wsMessage
+	if err := json.Unmarshal(payload, &msg); err != nil {
+		c.reportError(fmt.Errorf("invalid websocket message: %w", err))
+		return
+	}
+
+	if msg.Method == "eth_subscription" {
+		c.dispatchNotification(msg.Params)
+		return
+	}
+
+	id, err := strconv.ParseUint(string(msg.ID), 10, 64)
+	if err != nil {
+		c.reportError(fmt.Errorf("websocket response has invalid id %s", string(msg.ID)))
+		return
+	}
+
+	c.mu.Lock()
+	pending, ok := c.pending[id]
+	delete(c.pending, id)
+	if ok && pending.subscription != nil && msg.Error == nil && !pending.subscription.stopped {
+		var nodeID string
+		if err := json.Unmarshal(msg.Result, &nodeID); err == nil {
+			pending.subscription.nodeID = nodeID
+			c.subs[nodeID] = pending.subscription
+		}
+	}
+	c.mu.Unlock()
+
+	if ok {
+		pending.results <- wsResult{response: rpcResponse{Result: msg.Result, Error: msg.Error}}
+	}
+}
+
+func (c *WSClient) dispatchNotification(params json.RawMessage) {
+	var notification struct {
+		Subscription string `json:"subscription"`
+		Result       rpcLog `json:"result"`
+	}
+	if err := json.Unmarshal(params, &notification); err != nil {
+		c.reportError(fmt.Errorf("invalid subscription notification: %w", err))
+		return
+	}
+
+	event, err := notification.Result.toEvent()
+	if err != nil {
+		c.reportError(fmt.Errorf("invalid log notification: %w", err))
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	record, ok := c.subs[notification.Subscription]
+	if !ok || record.stopped {
+		return
+	}
+
+	select {
+	case record.subscription.Channel <- *event:
+	default:
+		c.reportError(fmt.Errorf("subscription %s channel is full, dropping event", record.nodeID))
+	}
+}
+
+func (c *WSClient) handleDisconnect(conn *wsConn, cause error) {
+	c.mu.Lock()
+	if c.closed || c.conn != conn {
+		c.mu.Unlock()
+		return
+	}
+	c.conn = nil
+	c.failPending(fmt.Errorf("websocket disconnected: %w", cause))
+	c.mu.Unlock()
+
+	c.reportError(fmt.Errorf("websocket disconnected, reconnecting: %w", cause))
+	go c.reconnect()
+}
+
+func (c *WSClient) reconnect() {
+	delay := c.ReconnectDelay
+	if delay <= 0 {
+		delay = DefaultReconnectDelay
+	}
+
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-time.After(delay):
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		conn, err := dialWebSocket(ctx, c.url)
+		cancel()
+		if err != nil {
+			c.reportError(err)
+			delay *= 2
+			if delay > MaxReconnectDelay {
+				delay = MaxReconnectDelay
+			}
+			continue
+		}
+
+		c.mu.Lock()
+		if c.closed {
+			c.mu.Unlock()
+			conn.Close()
+			return
+		}
+		c.conn = conn
+		for _, record := range c.subs {
+			c.retry = append(c.retry, record)
+		}
+		c.subs = make(map[string]*wsSubscription)
+		c.mu.Unlock()
+
+		go c.readLoop(conn)
+		c.resubscribe(conn)
+		return
+	}
+}
+
+// resubscribe keeps records whose eth_subscribe failed in c.retry and tries
+// them again with backoff until they succeed, the connection drops (the next
+// reconnect picks them up) or the client is closed.
+func (c *WSClient) resubscribe(conn *wsConn) {
+	delay := c.ReconnectDelay
+	if delay <= 0 {
+		delay = DefaultReconnectDelay
+	}
+
+	for {
+		c.mu.Lock()
+		if c.closed || c.conn != conn {
+			c.mu.Unlock()
+			return
+		}
+		records := c.retry
+		c.retry = nil
+		c.mu.Unlock()
+
+		for _, record := range records {
+			c.resubscribeRecord(record)
+		}
+
+		c.mu.Lock()
+		remaining := len(c.retry)
+		c.mu.Unlock()
+		if remaining == 0 {
+			return
+		}
+
+		select {
+		case <-c.done:
+			return
+		case <-time.After(delay):
+		}
+		delay *= 2
+		if delay > MaxReconnectDelay {
+			delay = MaxReconnectDelay
+		}
+	}
+}
+
+func (c *WSClient) resubscribeRecord(record *wsSubscription) {
+	c.mu.Lock()
+	oldID := record.nodeID
+	skip := record.stopped || (oldID != "" && c.subs[oldID] == record)
+	c.mu.Unlock()
+	if skip {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	nodeID, err := c.subscribe(ctx, record)
+	cancel()
+	if err != nil {
+		c.mu.Lock()
+		if !record.stopped {
+			c.retry = append(c.retry, record)
+		}
+		c.mu.Unlock()
+		c.reportError(fmt.Errorf("failed to resubscribe %s, will retry: %w", oldID, err))
+		return
+	}
+
+	c.mu.Lock()
+	registered := c.subs[nodeID] == record
+	c.mu.Unlock()
+
+	if !registered {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		c.unsubscribeNode(ctx, nodeID)
+		cancel()
+	}
+}
+
+func (c *WSClient) removePending(id uint64) {
+	c.mu.Lock()
+	delete(c.pending, id)
+	c.mu.Unlock()
+}
+
+func (c *WSClient) failPending(err error) {
+	for id, pending := range c.pending {
+		pending.results <- wsResult{err: err}
+		delete(c.pending, id)
+	}
+}
+
+func (c *WSClient) reportError(err error) {
+	select {
+	case c.errors <- err:
+	default:
+	}
+}
@@ -0,0 +1,193 @@
+package web3
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newWSTestServer upgrades every incoming request by hand and hands the
+// server side of each connection to the test, which plays the node.
+func newWSTestServer(t *testing.T) (string, <-chan *wsConn) {
+	t.Helper()
+	conns := make(chan *wsConn, 4)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("Sec-WebSocket-Key")
+		if r.Header.Get("Upgrade") != "websocket" || key == "" {
+			http.Error(w, "not a websocket handshake", http.StatusBadRequest)
+			return
+		}
+		conn, rw, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			return
+		}
+		rw.WriteString("HTTP/1.1 101 Switching Protocols\r\n" +
+			"Upgrade: websocket\r\n" +
+			"Connection: Upgrade\r\n" +
+			"Sec-WebSocket-Accept: " + websocketAccept(key) + "\r\n\r\n")
+		rw.Flush()
+		conns <- &wsConn{conn: conn, reader: rw.Reader}
+	}))
+	t.Cleanup(server.Close)
+	return "ws" + strings.TrimPrefix(server.URL, "http"), conns
+}
+
+func acceptWSConn(t *testing.T, conns <-chan *wsConn) *wsConn {
+	t.Helper()
+	select {
+	case conn := <-conns:
+		t.Cleanup(func() { conn.conn.Close() })
+		return conn
+	case <-time.After(5 * time.Second):
+		t.Fatal("client did not connect")
+		return nil
+	}
+}
+
+func readWSRequest(t *testing.T, conn *wsConn) (json.RawMessage, string) {
+	t.Helper()
+	conn.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	payload, err := conn.readMessage()
+	if err != nil {
+		t.Fatalf("server failed to read request: %v", err)
+	}
+	var req struct {
+		ID     json.RawMessage `json:"id"`
+		Method string          `json:"method"`
+	}
+	if err := json.Unmarshal(payload, &req); err != nil {
+		t.Fatalf("invalid request %s: %v", payload, err)
+	}
+	return req.ID, req.Method
+}
+
+func writeWSJSON(t *testing.T, conn *wsConn, value interface{}) {
+	t.Helper()
+	payload, err := json.Marshal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.writeMessage(payload); err != nil {
+		t.Fatalf("server failed to write: %v", err)
+	}
+}
+
+func TestWSClientCallLargeMessage(t *testing.T) {
+	url, conns := newWSTestServer(t)
+	client, err := NewWSClient(context.Background(), url)
+	if err != nil {
+		t.Fatalf("NewWSClient failed: %v", err)
+	}
+	defer client.Close()
+	conn := acceptWSConn(t, conns)
+
+	// 70000 bytes needs the 64-bit extended payload length.
+	large := strings.Repeat("a", 70000)
+	go func() {
+		id, _ := readWSRequest(t, conn)
+		writeWSJSON(t, conn, map[string]interface{}{"jsonrpc": "2.0", "id": id, "result": large})
+	}()
+
+	result, err := client.CallContext(context.Background(), "web3_clientVersion")
+	if err != nil {
+		t.Fatalf("CallContext failed: %v", err)
+	}
+	var version string
+	if err := json.Unmarshal(result, &version); err != nil || version != large {
+		t.Errorf("result has %d bytes, want %d", len(version), len(large))
+	}
+}
+
+func TestWSClientResubscribeRetriesAfterFailure(t *testing.T) {
+	url, conns := newWSTestServer(t)
+	client, err := NewWSClient(context.Background(), url)
+	if err != nil {
+		t.Fatalf("NewWSClient failed: %v", err)
+	}
+	defer client.Close()
+	client.ReconnectDelay = 10 * time.Millisecond
+
+	first := acceptWSConn(t, conns)
+	go func() {
+		id, _ := readWSRequest(t, first)
+		writeWSJSON(t, first, map[string]interface{}{"jsonrpc": "2.0", "id": id, "result": "0xaaa"})
+	}()
+	sub, err := client.SubscribeLogs(NewEventFilter())
+	if err != nil {
+		t.Fatalf("SubscribeLogs failed: %v", err)
+	}
+	if sub.ID != "0xaaa" {
+		t.Fatalf("subscription ID = %s, want 0xaaa", sub.ID)
+	}
+
+	// Drop the connection; the first eth_subscribe on the new one fails and
+	// the record must be retried rather than forgotten.
+	first.conn.Close()
+	second := acceptWSConn(t, conns)
+
+	id, method := readWSRequest(t, second)
+	if method != "eth_subscribe" {
+		t.Fatalf("method = %s, want eth_subscribe", method)
+	}
+	writeWSJSON(t, second, map[string]interface{}{
+		"jsonrpc": "2.0", "id": id,
+		"error": map[string]interface{}{"code": -32000, "message": "too many subscriptions"},
+	})
+
+	id, method = readWSRequest(t, second)
+	if method != "eth_subscribe" {
+		t.Fatalf("retry method = %s, want eth_subscribe", method)
+	}
+	writeWSJSON(t, second, map[string]interface{}{"jsonrpc": "2.0", "id": id, "result": "0xbbb"})
+
+	writeWSJSON(t, second, map[string]interface{}{
+		"jsonrpc": "2.0",
+		"method":  "eth_subscription",
+		"params": map[string]interface{}{
+			"subscription": "0xbbb",
+			"result": map[string]interface{}{
+				"address":          testTokenAddress,
+				"topics":           []string{ERC20_TRANSFER_SIGNATURE},
+				"data":             "0x",
+				"blockNumber":      "0x10",
+				"blockHash":        "0x" + strings.Repeat("11", 32),
+				"transactionHash":  testTxHash,
+				"transactionIndex": "0x0",
+				"logIndex":         "0x1",
+			},
+		},
+	})
+
+	select {
+	case event := <-sub.Channel:
+		if event.BlockNumber.Int64() != 16 || event.LogIndex != 1 {
+			t.Errorf("event block %s index %d, want 16 and 1", event.BlockNumber, event.LogIndex)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event delivered after resubscribe")
+	}
+
+	client.mu.Lock()
+	record := client.subs["0xbbb"]
+	client.mu.Unlock()
+	if record == nil || record.subscription != sub {
+		t.Error("resubscribed record is not tracked under the new node id")
+	}
+	if sub.ID != "0xaaa" {
+		t.Errorf("subscription ID = %s, want it to stay 0xaaa", sub.ID)
+	}
+
+	var sawRetry bool
+	for len(client.Errors()) > 0 {
+		if err := <-client.Errors(); strings.Contains(err.Error(), "will retry") {
+			sawRetry = true
+		}
+	}
+	if !sawRetry {
+		t.Error("expected a resubscribe retry error on Errors()")
+	}
+}